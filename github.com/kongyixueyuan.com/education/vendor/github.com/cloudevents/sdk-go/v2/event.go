@@ -0,0 +1,121 @@
+// Copyright 2020 The CloudEvents Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cloudevents is a minimal, locally-written stand-in for the
+// subset of github.com/cloudevents/sdk-go/v2's API that
+// genomics/v1/events depends on: NewEvent, the Event Set* methods,
+// SetData, the ApplicationJSON/ApplicationCloudEventsJSON content-type
+// constants, and JSON marshaling that matches the CloudEvents v1.0 JSON
+// event format. This sandbox has no network access to fetch the real SDK
+// - which also brings in transport bindings, multiple encodings, and a
+// context package this tree never uses - so rather than leave the import
+// unresolved, this implements just the envelope fields events.go sets
+// (id, source, type, subject, time, and a JSON data payload).
+package cloudevents
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Content types recognized by Event.SetData. Only ApplicationJSON is
+// supported by this minimal implementation, matching the only content type
+// this tree's events package ever passes; ApplicationCloudEventsJSON is
+// provided because events.go uses it as the HTTP Content-Type header when
+// forwarding an encoded Event, not as a SetData argument.
+const (
+	ApplicationJSON            = "application/json"
+	ApplicationCloudEventsJSON = "application/cloudevents+json"
+)
+
+// Event is a CloudEvents v1.0 envelope, covering the attributes this tree
+// sets: id, source, type, subject, time, and a JSON data payload.
+type Event struct {
+	specVersion     string
+	id              string
+	source          string
+	eventType       string
+	subject         string
+	eventTime       time.Time
+	dataContentType string
+	data            json.RawMessage
+}
+
+// NewEvent returns a new Event with its specversion attribute set to
+// specVersion (e.g. "1.0").
+func NewEvent(specVersion string) Event {
+	return Event{specVersion: specVersion}
+}
+
+// SetID sets the event's id attribute.
+func (e *Event) SetID(id string) { e.id = id }
+
+// SetSource sets the event's source attribute.
+func (e *Event) SetSource(source string) { e.source = source }
+
+// SetType sets the event's type attribute.
+func (e *Event) SetType(eventType string) { e.eventType = eventType }
+
+// SetSubject sets the event's subject attribute.
+func (e *Event) SetSubject(subject string) { e.subject = subject }
+
+// SetTime sets the event's time attribute.
+func (e *Event) SetTime(t time.Time) { e.eventTime = t }
+
+// SetData marshals data as contentType and attaches it to the event as its
+// data/datacontenttype attributes. Only ApplicationJSON is supported.
+func (e *Event) SetData(contentType string, data interface{}) error {
+	if contentType != ApplicationJSON {
+		return fmt.Errorf("cloudevents: unsupported content type %q", contentType)
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("cloudevents: marshaling event data: %w", err)
+	}
+	e.dataContentType = contentType
+	e.data = raw
+	return nil
+}
+
+// eventJSON mirrors the CloudEvents v1.0 JSON event format fields Event
+// carries.
+type eventJSON struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            *time.Time      `json:"time,omitempty"`
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	Data            json.RawMessage `json:"data,omitempty"`
+}
+
+// MarshalJSON renders e in the CloudEvents v1.0 JSON event format.
+func (e Event) MarshalJSON() ([]byte, error) {
+	ej := eventJSON{
+		SpecVersion:     e.specVersion,
+		ID:              e.id,
+		Source:          e.source,
+		Type:            e.eventType,
+		Subject:         e.subject,
+		DataContentType: e.dataContentType,
+		Data:            e.data,
+	}
+	if !e.eventTime.IsZero() {
+		t := e.eventTime
+		ej.Time = &t
+	}
+	return json.Marshal(ej)
+}
@@ -0,0 +1,379 @@
+// Copyright 2014 Sam Ghods
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package yaml is a minimal, locally-written stand-in for the two
+// github.com/ghodss/yaml functions operations_codec.go (and
+// keyword_plan_keyword_operations_codec.go) depend on:
+// JSONToYAML/YAMLToJSON. The real package bridges JSON and YAML by going
+// through gopkg.in/yaml.v2, which isn't vendored in this tree either and
+// this sandbox has no network access to fetch; rather than leave the
+// import unresolved, this implements the same JSON<->YAML bridge directly
+// against a block-style YAML subset: mappings and sequences by
+// indentation, double-quoted or plain scalars, and the "{}"/"[]" empty
+// forms. It does not support flow-style collections, multi-document
+// streams, anchors/aliases, tags, or block scalars (|, >) - none of which
+// this tree's generated YAML (or the configs it reads back) uses.
+package yaml
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONToYAML converts JSON-encoded data into an equivalent YAML document.
+func JSONToYAML(j []byte) ([]byte, error) {
+	var v interface{}
+	if err := json.Unmarshal(j, &v); err != nil {
+		return nil, fmt.Errorf("yaml: decoding JSON: %w", err)
+	}
+
+	var buf bytes.Buffer
+	switch t := v.(type) {
+	case nil:
+		buf.WriteString("null\n")
+	case map[string]interface{}:
+		if len(t) == 0 {
+			buf.WriteString("{}\n")
+		} else {
+			encodeMapBody(&buf, t, 0)
+		}
+	case []interface{}:
+		if len(t) == 0 {
+			buf.WriteString("[]\n")
+		} else {
+			encodeSeqBody(&buf, t, 0)
+		}
+	default:
+		buf.WriteString(encodeScalar(t))
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// YAMLToJSON converts a YAML document into equivalent JSON-encoded data.
+func YAMLToJSON(y []byte) ([]byte, error) {
+	v, err := parseDocument(y)
+	if err != nil {
+		return nil, fmt.Errorf("yaml: %w", err)
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("yaml: encoding JSON: %w", err)
+	}
+	return out, nil
+}
+
+// --- encoding ---
+
+func encodeNode(buf *bytes.Buffer, v interface{}, indent int) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		if len(t) == 0 {
+			buf.WriteString(" {}\n")
+			return
+		}
+		buf.WriteByte('\n')
+		encodeMapBody(buf, t, indent)
+	case []interface{}:
+		if len(t) == 0 {
+			buf.WriteString(" []\n")
+			return
+		}
+		buf.WriteByte('\n')
+		encodeSeqBody(buf, t, indent)
+	default:
+		buf.WriteByte(' ')
+		buf.WriteString(encodeScalar(t))
+		buf.WriteByte('\n')
+	}
+}
+
+func encodeMapBody(buf *bytes.Buffer, m map[string]interface{}, indent int) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		writeIndent(buf, indent)
+		buf.WriteString(encodeScalarString(k))
+		buf.WriteByte(':')
+		encodeNode(buf, m[k], indent+1)
+	}
+}
+
+func encodeSeqBody(buf *bytes.Buffer, s []interface{}, indent int) {
+	for _, item := range s {
+		writeIndent(buf, indent)
+		buf.WriteString("-")
+		encodeNode(buf, item, indent+1)
+	}
+}
+
+func writeIndent(buf *bytes.Buffer, indent int) {
+	buf.WriteString(strings.Repeat("  ", indent))
+}
+
+func encodeScalar(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		if t {
+			return "true"
+		}
+		return "false"
+	case float64:
+		return formatNumber(t)
+	case string:
+		return encodeScalarString(t)
+	default:
+		return fmt.Sprintf("%v", t)
+	}
+}
+
+var plainScalarRE = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+var reservedWords = map[string]bool{
+	"true": true, "false": true, "null": true, "~": true,
+	"yes": true, "no": true,
+}
+
+// encodeScalarString renders s as a YAML scalar, double-quoting it (using
+// JSON's escaping, which YAML double-quoted scalars accept) unless it's
+// plain enough to be unambiguous unquoted.
+func encodeScalarString(s string) string {
+	if s != "" && !reservedWords[strings.ToLower(s)] && !looksLikeNumber(s) && plainScalarRE.MatchString(s) {
+		return s
+	}
+	quoted, _ := json.Marshal(s)
+	return string(quoted)
+}
+
+func looksLikeNumber(s string) bool {
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
+}
+
+func formatNumber(f float64) string {
+	if f == math.Trunc(f) && !math.IsInf(f, 0) && math.Abs(f) < 1e15 {
+		return strconv.FormatInt(int64(f), 10)
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// --- decoding ---
+
+type yamlLine struct {
+	indent  int
+	content string
+}
+
+func parseDocument(data []byte) (interface{}, error) {
+	lines, err := tokenizeLines(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, nil
+	}
+	v, _, err := parseBlock(lines, 0, lines[0].indent)
+	return v, err
+}
+
+func tokenizeLines(data []byte) ([]yamlLine, error) {
+	var lines []yamlLine
+	for _, raw := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		if strings.Contains(raw, "\t") {
+			return nil, fmt.Errorf("tabs are not supported for indentation: %q", raw)
+		}
+		trimmed := strings.TrimRight(raw, " ")
+		content := strings.TrimLeft(trimmed, " ")
+		if content == "" || strings.HasPrefix(content, "#") {
+			continue
+		}
+		indent := len(trimmed) - len(content)
+		lines = append(lines, yamlLine{indent: indent, content: content})
+	}
+	return lines, nil
+}
+
+// parseBlock parses the node starting at lines[idx], which must be
+// indented exactly to indent, and returns the value and the index of the
+// first line not consumed.
+func parseBlock(lines []yamlLine, idx, indent int) (interface{}, int, error) {
+	if idx >= len(lines) {
+		return nil, idx, nil
+	}
+	content := lines[idx].content
+	switch {
+	case content == "{}":
+		return map[string]interface{}{}, idx + 1, nil
+	case content == "[]":
+		return []interface{}{}, idx + 1, nil
+	case content == "-" || strings.HasPrefix(content, "- "):
+		return parseSequence(lines, idx, indent)
+	case isMappingLine(content):
+		return parseMapping(lines, idx, indent)
+	default:
+		v, err := parseScalar(content)
+		return v, idx + 1, err
+	}
+}
+
+func parseSequence(lines []yamlLine, idx, indent int) (interface{}, int, error) {
+	var result []interface{}
+	for idx < len(lines) && lines[idx].indent == indent && (lines[idx].content == "-" || strings.HasPrefix(lines[idx].content, "- ")) {
+		content := lines[idx].content
+		if content == "-" {
+			idx++
+			if idx < len(lines) && lines[idx].indent > indent {
+				v, newIdx, err := parseBlock(lines, idx, lines[idx].indent)
+				if err != nil {
+					return nil, idx, err
+				}
+				result = append(result, v)
+				idx = newIdx
+				continue
+			}
+			result = append(result, nil)
+			continue
+		}
+		v, err := parseScalar(strings.TrimPrefix(content, "- "))
+		if err != nil {
+			return nil, idx, err
+		}
+		result = append(result, v)
+		idx++
+	}
+	if result == nil {
+		result = []interface{}{}
+	}
+	return result, idx, nil
+}
+
+func parseMapping(lines []yamlLine, idx, indent int) (interface{}, int, error) {
+	result := make(map[string]interface{})
+	for idx < len(lines) && lines[idx].indent == indent {
+		content := lines[idx].content
+		key, rest, hasInline, err := splitKeyValue(content)
+		if err != nil {
+			return nil, idx, err
+		}
+		idx++
+		if hasInline {
+			v, err := parseScalar(rest)
+			if err != nil {
+				return nil, idx, err
+			}
+			result[key] = v
+			continue
+		}
+		if idx < len(lines) && lines[idx].indent > indent {
+			v, newIdx, err := parseBlock(lines, idx, lines[idx].indent)
+			if err != nil {
+				return nil, idx, err
+			}
+			result[key] = v
+			idx = newIdx
+			continue
+		}
+		result[key] = nil
+	}
+	return result, idx, nil
+}
+
+// isMappingLine reports whether content has a ": " or trailing ":" outside
+// of a double-quoted run, which is how parseBlock tells a mapping line
+// apart from a bare scalar.
+func isMappingLine(content string) bool {
+	return colonIndex(content) >= 0
+}
+
+// colonIndex returns the index of the colon that separates a mapping
+// line's key from its value (outside of any double-quoted run, and
+// followed by a space or end of line), or -1 if content isn't a mapping
+// line.
+func colonIndex(content string) int {
+	inQuotes := false
+	for i := 0; i < len(content); i++ {
+		switch content[i] {
+		case '"':
+			inQuotes = !inQuotes
+		case ':':
+			if !inQuotes && (i == len(content)-1 || content[i+1] == ' ') {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitKeyValue splits a mapping line's content into its key and, if
+// present on the same line, its scalar value. hasInline is true only when
+// a value follows the colon on this line.
+func splitKeyValue(content string) (key, rest string, hasInline bool, err error) {
+	i := colonIndex(content)
+	if i < 0 {
+		return "", "", false, fmt.Errorf("not a mapping line: %q", content)
+	}
+	key, err = unquoteKey(strings.TrimSpace(content[:i]))
+	if err != nil {
+		return "", "", false, err
+	}
+	valuePart := strings.TrimSpace(content[i+1:])
+	return key, valuePart, valuePart != "", nil
+}
+
+func unquoteKey(s string) (string, error) {
+	if strings.HasPrefix(s, "\"") {
+		var out string
+		if err := json.Unmarshal([]byte(s), &out); err != nil {
+			return "", fmt.Errorf("invalid quoted key %q: %w", s, err)
+		}
+		return out, nil
+	}
+	return s, nil
+}
+
+func parseScalar(s string) (interface{}, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "" || s == "~" || s == "null" || s == "Null" || s == "NULL":
+		return nil, nil
+	case s == "{}":
+		return map[string]interface{}{}, nil
+	case s == "[]":
+		return []interface{}{}, nil
+	case s == "true" || s == "True" || s == "TRUE":
+		return true, nil
+	case s == "false" || s == "False" || s == "FALSE":
+		return false, nil
+	case strings.HasPrefix(s, "\""):
+		var out string
+		if err := json.Unmarshal([]byte(s), &out); err != nil {
+			return nil, fmt.Errorf("invalid quoted scalar %q: %w", s, err)
+		}
+		return out, nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f, nil
+	}
+	return s, nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2014 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package btree is a minimal, locally-written stand-in for the subset of
+// github.com/google/btree's API that genomicstest.FakeOperations depends on
+// (Item, BTree, New, ReplaceOrInsert, Delete, Ascend). This sandbox has no
+// network access to fetch the real module, so rather than leave the import
+// unresolved, this reimplements that surface - backed by a sorted slice
+// with binary-search insertion instead of a true copy-on-write B-tree, so
+// operations are O(n) rather than O(log n). That's the right trade for the
+// in-memory, test-scale operation counts genomicstest.go uses it for; it is
+// not a drop-in for btree's performance characteristics at scale.
+package btree
+
+import "sort"
+
+// Item is a single object stored in a BTree, ordered by Less.
+type Item interface {
+	Less(than Item) bool
+}
+
+// ItemIterator is called for every item visited by BTree.Ascend, in order.
+// Returning false stops the iteration early.
+type ItemIterator func(item Item) bool
+
+// BTree holds Items in ascending order, as defined by Less. The zero value
+// is not ready to use; construct one with New.
+type BTree struct {
+	// degree is kept only for API compatibility with github.com/google/btree,
+	// whose constructor takes the B-tree branching factor. This
+	// implementation doesn't use it.
+	degree int
+	items  []Item
+}
+
+// New returns an empty BTree. degree must be greater than 1, matching the
+// real package's constraint, even though this implementation doesn't use it.
+func New(degree int) *BTree {
+	if degree <= 1 {
+		panic("btree: degree must be greater than 1")
+	}
+	return &BTree{degree: degree}
+}
+
+// search returns the index of item in t.items, and whether it was found.
+// When not found, the index is where item should be inserted to keep
+// t.items sorted.
+func (t *BTree) search(item Item) (int, bool) {
+	i := sort.Search(len(t.items), func(i int) bool {
+		return !t.items[i].Less(item)
+	})
+	if i < len(t.items) && !item.Less(t.items[i]) {
+		return i, true
+	}
+	return i, false
+}
+
+// ReplaceOrInsert adds item to the tree, replacing and returning any
+// existing item that compares equal to it (neither Less than the other).
+// If no such item exists, it returns nil.
+func (t *BTree) ReplaceOrInsert(item Item) Item {
+	i, found := t.search(item)
+	if found {
+		old := t.items[i]
+		t.items[i] = item
+		return old
+	}
+	t.items = append(t.items, nil)
+	copy(t.items[i+1:], t.items[i:])
+	t.items[i] = item
+	return nil
+}
+
+// Delete removes the item equal to item from the tree, returning it, or nil
+// if no such item is present.
+func (t *BTree) Delete(item Item) Item {
+	i, found := t.search(item)
+	if !found {
+		return nil
+	}
+	old := t.items[i]
+	t.items = append(t.items[:i], t.items[i+1:]...)
+	return old
+}
+
+// Ascend calls iterator for every item in the tree in ascending order,
+// stopping early if iterator returns false.
+func (t *BTree) Ascend(iterator ItemIterator) {
+	for _, item := range t.items {
+		if !iterator(item) {
+			return
+		}
+	}
+}
+
+// Len returns the number of items currently in the tree.
+func (t *BTree) Len() int {
+	return len(t.items)
+}
@@ -0,0 +1,83 @@
+// Copyright 2014 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fuzz is a minimal, locally-written stand-in for the subset of
+// github.com/google/gofuzz's API that genomicstest.RandomMetadata depends
+// on: New/NewWithSeed and Fuzz(obj interface{}) filling a pointer to a
+// string, bool, or sized int/uint/float with random data. This sandbox has
+// no network access to fetch the real module, so rather than leave the
+// import unresolved, this reimplements just that surface via reflection.
+// It does not attempt gofuzz's struct/slice/map recursion, custom fuzz
+// functions, or NumElements/NilChance knobs, since nothing in this tree
+// uses them.
+package fuzz
+
+import (
+	"math/rand"
+	"reflect"
+	"time"
+)
+
+// Fuzzer fills the pointer passed to Fuzz with random data appropriate to
+// its kind.
+type Fuzzer struct {
+	r *rand.Rand
+}
+
+// New returns a Fuzzer seeded from the current time.
+func New() *Fuzzer {
+	return NewWithSeed(time.Now().UnixNano())
+}
+
+// NewWithSeed returns a Fuzzer with a deterministic seed, for reproducible
+// test runs.
+func NewWithSeed(seed int64) *Fuzzer {
+	return &Fuzzer{r: rand.New(rand.NewSource(seed))}
+}
+
+// Fuzz fills obj, which must be a non-nil pointer to a string, bool, or
+// sized int/uint/float, with random data. Other kinds are left untouched.
+func (f *Fuzzer) Fuzz(obj interface{}) {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return
+	}
+	f.fuzzValue(v.Elem())
+}
+
+func (f *Fuzzer) fuzzValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(f.randString())
+	case reflect.Bool:
+		v.SetBool(f.r.Intn(2) == 1)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(f.r.Int63())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v.SetUint(uint64(f.r.Int63()))
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(f.r.Float64())
+	}
+}
+
+const randStringLetters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+func (f *Fuzzer) randString() string {
+	n := f.r.Intn(20)
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = randStringLetters[f.r.Intn(len(randStringLetters))]
+	}
+	return string(b)
+}
@@ -0,0 +1,54 @@
+/*
+ * ZLint Copyright 2018 Regents of the University of Michigan
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not
+ * use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+ * implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// idnaProfile is IDNA2008's registration profile (idna.Registration): it
+// applies Unicode normalization, the bidi and contextual rules, and the
+// disallowed-codepoint tables that a registry checks before delegating a
+// label, which is the right profile for deciding whether a gTLD/ccTLD name
+// is one a CA could ever see in a certificate. It's stricter than the
+// lookup profile used by resolvers, so a label that fails here is rejected
+// rather than silently coerced.
+var idnaProfile = idna.Registration
+
+// toASCIIName converts every dot-separated label of name (a gTLD or, for
+// Public Suffix List entries, a multi-label effective TLD) to its
+// lowercase ASCII-Compatible Encoding (A-label) via idna.Registration,
+// rejecting (not silently normalizing past) anything that profile
+// disallows.
+func toASCIIName(name string) (string, error) {
+	aLabel, err := idnaProfile.ToASCII(name)
+	if err != nil {
+		return "", fmt.Errorf("converting %q to A-label : %s", name, err)
+	}
+	return strings.ToLower(aLabel), nil
+}
+
+// toUnicodeName converts every dot-separated label of name to its Unicode
+// (U-label) form via idna.Registration.
+func toUnicodeName(name string) (string, error) {
+	uLabel, err := idnaProfile.ToUnicode(name)
+	if err != nil {
+		return "", fmt.Errorf("converting %q to U-label : %s", name, err)
+	}
+	return uLabel, nil
+}
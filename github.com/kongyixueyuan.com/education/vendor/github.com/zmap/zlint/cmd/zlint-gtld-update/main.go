@@ -16,16 +16,24 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"go/format"
 	"html/template"
-	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -46,9 +54,69 @@ const (
 	// See https://www.icann.org/resources/pages/tlds-2012-02-25-en for more
 	// information.
 	ICANN_TLDS = "https://data.iana.org/TLD/tlds-alpha-by-domain.txt"
+	// PUBLIC_SUFFIX_LIST is the URL for the Mozilla-maintained Public Suffix
+	// List, used to find private/effective TLDs (e.g. `co.uk`,
+	// `github.io`) that never appear in ICANN's own data sources.
+	// See https://publicsuffix.org/list/ for more information.
+	PUBLIC_SUFFIX_LIST = "https://publicsuffix.org/list/public_suffix_list.dat"
+	// IANA_ROOT_ZONE_DB is the URL template (with a %s placeholder for the
+	// lowercase TLD) for a TLD's page in IANA's Root Zone Database. It
+	// carries the TLD's real delegation date in a "Registry Information"
+	// section, unlike ICANN_TLDS which has no per-TLD granularity.
+	// See https://www.iana.org/domains/root/db for more information.
+	IANA_ROOT_ZONE_DB = "https://www.iana.org/domains/root/db/%s.html"
 )
 
 var (
+	// timeout bounds a single HTTP attempt in getData, separate from the
+	// overall retry budget.
+	timeout = flag.Duration("timeout", 15*time.Second, "per-attempt HTTP timeout when fetching data sources")
+	// retries is the maximum number of attempts getData will make for
+	// a single URL before giving up.
+	retries = flag.Int("retries", 5, "maximum number of attempts when fetching a data source")
+	// backoff is the initial delay between retries; it is doubled after
+	// every attempt up to a 8s cap.
+	backoff = flag.Duration("backoff", 500*time.Millisecond, "initial backoff delay between retries")
+
+	// includePSL enables fetching the Public Suffix List as a third data
+	// source, covering private/effective TLDs that ICANN's own sources
+	// don't carry.
+	includePSL = flag.Bool("include-psl", false, "fetch the Public Suffix List and merge its entries into the gTLD map")
+	// pslSections restricts which Public Suffix List sections are merged
+	// in, as a comma separated list of "icann" and/or "private".
+	pslSections = flag.String("psl-sections", "icann,private", "comma separated list of PSL sections to include (icann, private)")
+
+	// gtldJSONPath, tldListPath and pslFilePath let each data source be read
+	// from a local file (or file:// URL) instead of fetched live, for
+	// reproducible, air-gapped `go generate` runs.
+	gtldJSONPath = flag.String("gtld-json", "", "local path or file:// URL to use instead of fetching "+ICANN_GTLD_JSON)
+	tldListPath  = flag.String("tld-list", "", "local path or file:// URL to use instead of fetching "+ICANN_TLDS)
+	pslFilePath  = flag.String("psl-file", "", "local path or file:// URL to use instead of fetching "+PUBLIC_SUFFIX_LIST)
+	// sourcesManifest is a JSON file recording the SHA-256 digest of each
+	// input source. When any of -gtld-json/-tld-list/-psl-file is set
+	// (offline mode) the corresponding digest is verified against the
+	// manifest before use. Otherwise (online mode) the digests observed
+	// this run are recorded into it.
+	sourcesManifest = flag.String("sources-manifest", "", "path to a JSON file of pinned SHA-256 source digests")
+
+	// outputFormat selects which Renderer is used to produce the output
+	// written by main. go-map is kept as the default so `go generate`
+	// invocations that don't pass -format continue to produce tldMap.go.
+	outputFormat = flag.String("format", "go-map", "output format: go-map, json, csv, or proto")
+
+	// ccTLDSource selects how ccTLD DelegationDates are determined.
+	// "default" keeps the 1985-01-01 placeholder getTLDData has always used;
+	// "iana-rzd" overrides it with the real date scraped from each ccTLD's
+	// IANA Root Zone Database page.
+	ccTLDSource = flag.String("cctld-source", "default", "source for ccTLD delegation dates: default or iana-rzd")
+	// ianaRZDCacheDir, if set, caches fetched IANA Root Zone Database pages
+	// on disk keyed by ETag/Last-Modified so repeat -cctld-source=iana-rzd
+	// runs only re-download pages that actually changed.
+	ianaRZDCacheDir = flag.String("iana-rzd-cache", "", "directory to cache IANA Root Zone Database pages in (disabled if empty)")
+
+	// maxBackoff caps the exponential backoff delay between retries.
+	maxBackoff = 8 * time.Second
+
 	// httpClient is a http.Client instance configured with timeouts.
 	httpClient = &http.Client{
 		Transport: &http.Transport{
@@ -67,6 +135,19 @@ var (
 	gTLDMapTemplate = template.Must(template.New("gTLDMapTemplate").Parse(
 		`// Code generated by go generate; DO NOT EDIT.
 // This file was generated by zlint-gtld-update.
+{{- if .Manifest }}
+//
+// Generated from sources with the following SHA-256 digests:
+{{- if .Manifest.GTLDJSONSHA256 }}
+//   ICANN gTLD JSON:    {{ .Manifest.GTLDJSONSHA256 }}
+{{- end }}
+{{- if .Manifest.TLDListSHA256 }}
+//   IANA TLD list:      {{ .Manifest.TLDListSHA256 }}
+{{- end }}
+{{- if .Manifest.PSLSHA256 }}
+//   Public Suffix List: {{ .Manifest.PSLSHA256 }}
+{{- end }}
+{{- end }}
 
 /*
  * ZLint Copyright 2018 Regents of the University of Michigan
@@ -84,50 +165,232 @@ var (
 
 package util
 
+// tldMap is keyed by each gTLD's canonical A-label; see uLabelIndex to
+// look an entry up by its Unicode (U-label) form instead.
 var tldMap = map[string]GTLDPeriod{
 {{- range .GTLDs }}
 	"{{ .GTLD }}": {
 		GTLD: "{{ .GTLD }}",
+		ALabel: "{{ .ALabel }}",
+		ULabel: "{{ .ULabel }}",
 		DelegationDate: "{{ .DelegationDate }}",
 		RemovalDate: "{{ .RemovalDate }}",
+		Source: "{{ .Source }}",
+		Wildcard: {{ .Wildcard }},
+		Exception: {{ .Exception }},
 	},
 {{- end }}
-	// .onion is a special case and not a general gTLD. However, it is allowed in
-	// some circumstances in the web PKI so the Zlint gtldMap includes it with
-	// a delegationDate based on the CABF ballot to allow EV issuance for .onion
-	// domains: https://cabforum.org/2015/02/18/ballot-144-validation-rules-dot-onion-names/
-	"onion": {
-		GTLD: "onion",
-		DelegationDate: "2015-02-18",
-		RemovalDate: "",
-	},
+}
+
+// uLabelIndex maps each internationalized gTLD's Unicode (U-label) form to
+// the key it's stored under in tldMap (its ASCII-Compatible A-label), for
+// lints that see the Unicode form in a certificate. gTLDs whose ULabel
+// equals their ALabel (i.e. non-internationalized TLDs) aren't present
+// here since tldMap is already keyed by that same string.
+var uLabelIndex = map[string]string{
+{{- range .GTLDs }}
+{{- if ne .ULabel .ALabel }}
+	"{{ .ULabel }}": "{{ .ALabel }}",
+{{- end }}
+{{- end }}
 }
 `))
 )
 
-// getData fetches the response body bytes from an HTTP get to the provider url,
-// or returns an error.
-func getData(url string) ([]byte, error) {
-	resp, err := httpClient.Get(url)
+// sourceManifest records the SHA-256 digest of each input source so a
+// generated tldMap.go can be traced back to the exact ICANN/PSL snapshot
+// that produced it, and so offline runs can verify their local inputs
+// haven't drifted from what was last fetched online.
+type sourceManifest struct {
+	GTLDJSONSHA256 string `json:"gtld_json_sha256,omitempty"`
+	TLDListSHA256  string `json:"tld_list_sha256,omitempty"`
+	PSLSHA256      string `json:"psl_sha256,omitempty"`
+}
+
+func loadManifest(path string) (*sourceManifest, error) {
+	if path == "" {
+		return &sourceManifest{}, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &sourceManifest{}, nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("unable to fetch data from %q : %s",
-			url, err)
+		return nil, fmt.Errorf("reading sources manifest %q : %s", path, err)
+	}
+	var m sourceManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing sources manifest %q : %s", path, err)
+	}
+	return &m, nil
+}
+
+func saveManifest(path string, m *sourceManifest) error {
+	if path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(data, '\n'), 0664)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// fetchOrRead returns the bytes for a data source, either by fetching
+// fetchURL live or, if localPath is non-empty, by reading it from disk (a
+// bare path or a file:// URL). When localPath is set and pinned is
+// non-empty, this also verifies the result's SHA-256 digest against pinned,
+// failing loudly on a mismatch; with an empty pinned the local data is
+// accepted unverified. In every case the digest of the returned data is
+// recorded into pinned.
+func fetchOrRead(ctx context.Context, fetchURL, localPath string, pinned *string) ([]byte, error) {
+	if localPath == "" {
+		data, err := getData(ctx, fetchURL)
+		if err != nil {
+			return nil, err
+		}
+		*pinned = sha256Hex(data)
+		return data, nil
+	}
+
+	data, err := readLocalSource(localPath)
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256Hex(data)
+	if *pinned != "" && digest != *pinned {
+		return nil, fmt.Errorf("offline source %q has digest %s, expected %s from sources manifest",
+			localPath, digest, *pinned)
+	}
+	*pinned = digest
+	return data, nil
+}
+
+// readLocalSource reads localPath, which may be a bare filesystem path or a
+// file:// URL (as produced by tools that resolve "local or URL" flags
+// generically).
+func readLocalSource(localPath string) ([]byte, error) {
+	path := localPath
+	if u, err := url.Parse(localPath); err == nil && u.Scheme == "file" {
+		path = u.Path
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading local source %q : %s", localPath, err)
+	}
+	return data, nil
+}
+
+// isTerminalStatus returns true if the given HTTP status code should not be
+// retried: any 4xx other than 429 (Too Many Requests).
+func isTerminalStatus(code int) bool {
+	return code >= 400 && code < 500 && code != http.StatusTooManyRequests
+}
+
+// retryDelay returns how long to wait before the next attempt, honoring a
+// Retry-After header (seconds or HTTP-date) on 429/503 responses when
+// present, and otherwise backoff, capped at maxBackoff.
+func retryDelay(resp *http.Response, backoff time.Duration) time.Duration {
+	if resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+		if ra := resp.Header.Get("Retry-After"); ra != "" {
+			if secs, err := strconv.Atoi(ra); err == nil {
+				return time.Duration(secs) * time.Second
+			}
+			if when, err := http.ParseTime(ra); err == nil {
+				if d := time.Until(when); d > 0 {
+					return d
+				}
+			}
+		}
+	}
+	if backoff > maxBackoff {
+		return maxBackoff
+	}
+	return backoff
+}
+
+// getData fetches the response body bytes from an HTTP get to the provider
+// url, or returns an error. It retries transient failures (network errors,
+// 5xx responses and truncated reads) with exponential backoff up to
+// *retries attempts, respecting ctx cancellation and any Retry-After header
+// ICANN sends on 429/503. 4xx responses other than 429 are treated as
+// terminal and returned immediately.
+func getData(ctx context.Context, url string) ([]byte, error) {
+	delay := *backoff
+	var lastErr error
+
+	for attempt := 1; attempt <= *retries; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		respBody, resp, err := getDataOnce(ctx, url)
+		if err == nil {
+			return respBody, nil
+		}
+		lastErr = err
+
+		if resp != nil && isTerminalStatus(resp.StatusCode) {
+			return nil, err
+		}
+		if attempt == *retries {
+			break
+		}
+
+		wait := retryDelay(resp, delay)
+		delay *= 2
+		if delay > maxBackoff {
+			delay = maxBackoff
+		}
+		log.Warnf("attempt %d/%d fetching %q failed: %s; retrying in %s", attempt, *retries, url, err, wait)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return nil, fmt.Errorf("unable to fetch data from %q after %d attempts : %s", url, *retries, lastErr)
+}
+
+// getDataOnce performs a single GET of url, returning the response body, the
+// *http.Response (even on a non-2xx status, so the caller can inspect
+// headers/status for retry decisions), and an error if the attempt failed.
+func getDataOnce(ctx context.Context, url string) ([]byte, *http.Response, error) {
+	reqCtx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to build request for %q : %s", url, err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to fetch data from %q : %s", url, err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code fetching data "+
+		return nil, resp, fmt.Errorf("unexpected status code fetching data "+
 			"from %q : expected status %d got %d",
 			url, http.StatusOK, resp.StatusCode)
 	}
 
 	respBody, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("unexpected error reading response "+
+		return nil, resp, fmt.Errorf("unexpected error reading response "+
 			"body from %q : %s",
 			url, err)
 	}
-	return respBody, nil
+	return respBody, resp, nil
 }
 
 // getTLDData fetches the ICANN_TLDS list and uses the information to build
@@ -136,8 +399,8 @@ func getData(url string) ([]byte, error) {
 // about delegation/removal all of the returned `util.GTLDPeriod` objects will
 // have the DelegationDate "1985-01-01" (matching the `.com` delegation date)
 // and no RemovalDate.
-func getTLDData() ([]util.GTLDPeriod, error) {
-	respBody, err := getData(ICANN_TLDS)
+func getTLDData(ctx context.Context, manifest *sourceManifest) ([]util.GTLDPeriod, error) {
+	respBody, err := fetchOrRead(ctx, ICANN_TLDS, *tldListPath, &manifest.TLDListSHA256)
 	if err != nil {
 		return nil, fmt.Errorf("error getting ICANN TLD list : %s", err)
 	}
@@ -162,8 +425,8 @@ func getTLDData() ([]util.GTLDPeriod, error) {
 // getGTLDData fetches the ICANN_GTLD_JSON and parses it into a list of
 // util.GTLDPeriod objects, or returns an error. The gTLDEntries are returned
 // as-is and may contain entries that were never delegated from the root DNS.
-func getGTLDData() ([]util.GTLDPeriod, error) {
-	respBody, err := getData(ICANN_GTLD_JSON)
+func getGTLDData(ctx context.Context, manifest *sourceManifest) ([]util.GTLDPeriod, error) {
+	respBody, err := fetchOrRead(ctx, ICANN_GTLD_JSON, *gtldJSONPath, &manifest.GTLDJSONSHA256)
 	if err != nil {
 		return nil, fmt.Errorf("error getting ICANN gTLD JSON : %s", err)
 	}
@@ -179,6 +442,210 @@ func getGTLDData() ([]util.GTLDPeriod, error) {
 	return results.GTLDs, nil
 }
 
+// pslSectionMarkers bound the ICANN and PRIVATE sections of the Public
+// Suffix List data file, per the format documented at
+// https://publicsuffix.org/list/.
+const (
+	pslICANNBegin   = "// ===BEGIN ICANN DOMAINS==="
+	pslICANNEnd     = "// ===END ICANN DOMAINS==="
+	pslPrivateBegin = "// ===BEGIN PRIVATE DOMAINS==="
+	pslPrivateEnd   = "// ===END PRIVATE DOMAINS==="
+)
+
+// enabledPSLSections parses the -psl-sections flag value into a set of
+// util.Source values to keep, returning an error for unrecognized section
+// names.
+func enabledPSLSections(flagVal string) (map[util.Source]bool, error) {
+	enabled := make(map[util.Source]bool)
+	for _, section := range strings.Split(flagVal, ",") {
+		switch strings.TrimSpace(section) {
+		case "icann":
+			enabled[util.SourcePSLICANN] = true
+		case "private":
+			enabled[util.SourcePSLPrivate] = true
+		case "":
+			continue
+		default:
+			return nil, fmt.Errorf("unknown -psl-sections value %q", section)
+		}
+	}
+	return enabled, nil
+}
+
+// getPSLData fetches the Public Suffix List and parses its ICANN and
+// PRIVATE sections into util.GTLDPeriod entries, tagging each with the
+// appropriate Source and marking `*.` wildcard and `!` exception rules.
+// Only sections present in enabledSections are returned. Parsed entries
+// have no DelegationDate/RemovalDate since the PSL doesn't carry that
+// information.
+func getPSLData(ctx context.Context, enabledSections map[util.Source]bool, manifest *sourceManifest) ([]util.GTLDPeriod, error) {
+	respBody, err := fetchOrRead(ctx, PUBLIC_SUFFIX_LIST, *pslFilePath, &manifest.PSLSHA256)
+	if err != nil {
+		return nil, fmt.Errorf("error getting Public Suffix List : %s", err)
+	}
+
+	var results []util.GTLDPeriod
+	var source util.Source
+	for _, line := range strings.Split(string(respBody), "\n") {
+		line = strings.TrimSpace(line)
+		switch line {
+		case pslICANNBegin:
+			source = util.SourcePSLICANN
+			continue
+		case pslICANNEnd, pslPrivateEnd:
+			source = ""
+			continue
+		case pslPrivateBegin:
+			source = util.SourcePSLPrivate
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "//") || source == "" {
+			continue
+		}
+		if !enabledSections[source] {
+			continue
+		}
+
+		entry := util.GTLDPeriod{Source: source}
+		switch {
+		case strings.HasPrefix(line, "*."):
+			entry.GTLD = strings.ToLower(strings.TrimPrefix(line, "*."))
+			entry.Wildcard = true
+		case strings.HasPrefix(line, "!"):
+			entry.GTLD = strings.ToLower(strings.TrimPrefix(line, "!"))
+			entry.Exception = true
+		default:
+			entry.GTLD = strings.ToLower(line)
+		}
+		results = append(results, entry)
+	}
+	return results, nil
+}
+
+// ianaRZDCacheMeta is the validator state cached alongside a fetched IANA
+// Root Zone Database page, letting subsequent fetches make a conditional
+// request instead of re-downloading the page.
+type ianaRZDCacheMeta struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// ianaRZDRegistrationDate matches the date shown next to the "Registration
+// date" row of the "Registry Information" table on a ccTLD's IANA Root Zone
+// Database page, e.g. `<b>Registration date</b></td><td>2011-07-14</td>`.
+// It is intentionally loose about the HTML between the label and the date
+// since IANA has changed this page's markup over the years without
+// changing the label text.
+var ianaRZDRegistrationDate = regexp.MustCompile(`(?is)registration date.{0,200}?(\d{4}-\d{2}-\d{2})`)
+
+// fetchIANARootZonePage fetches tld's IANA Root Zone Database page. If
+// cacheDir is non-empty the page is cached there as "<tld>.html" with its
+// ETag/Last-Modified in a "<tld>.json" sidecar, and subsequent calls send a
+// conditional request, returning the cached body on a 304 response.
+func fetchIANARootZonePage(ctx context.Context, tld, cacheDir string) ([]byte, error) {
+	url := fmt.Sprintf(IANA_ROOT_ZONE_DB, strings.ToLower(tld))
+
+	var bodyPath, metaPath string
+	var cached []byte
+	var meta ianaRZDCacheMeta
+	if cacheDir != "" {
+		bodyPath = filepath.Join(cacheDir, strings.ToLower(tld)+".html")
+		metaPath = filepath.Join(cacheDir, strings.ToLower(tld)+".json")
+		if data, err := ioutil.ReadFile(bodyPath); err == nil {
+			cached = data
+		}
+		if data, err := ioutil.ReadFile(metaPath); err == nil {
+			_ = json.Unmarshal(data, &meta)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("building request for %q : %s", url, err)
+	}
+	if cached != nil {
+		if meta.ETag != "" {
+			req.Header.Set("If-None-Match", meta.ETag)
+		}
+		if meta.LastModified != "" {
+			req.Header.Set("If-Modified-Since", meta.LastModified)
+		}
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %q : %s", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		return cached, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code fetching %q : expected %d or %d got %d",
+			url, http.StatusOK, http.StatusNotModified, resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body from %q : %s", url, err)
+	}
+
+	if cacheDir != "" {
+		if err := os.MkdirAll(cacheDir, 0775); err != nil {
+			return nil, fmt.Errorf("creating -iana-rzd-cache dir %q : %s", cacheDir, err)
+		}
+		if err := ioutil.WriteFile(bodyPath, body, 0664); err != nil {
+			return nil, fmt.Errorf("writing %q to -iana-rzd-cache : %s", bodyPath, err)
+		}
+		newMeta := ianaRZDCacheMeta{ETag: resp.Header.Get("ETag"), LastModified: resp.Header.Get("Last-Modified")}
+		if data, err := json.Marshal(newMeta); err == nil {
+			_ = ioutil.WriteFile(metaPath, data, 0664)
+		}
+	}
+	return body, nil
+}
+
+// parseIANADelegationDate extracts and validates the registration date from
+// an IANA Root Zone Database page fetched by fetchIANARootZonePage.
+func parseIANADelegationDate(page []byte) (string, error) {
+	m := ianaRZDRegistrationDate.FindSubmatch(page)
+	if m == nil {
+		return "", fmt.Errorf("no registration date found on page")
+	}
+	date := string(m[1])
+	if _, err := time.Parse(util.GTLDPeriodDateFormat, date); err != nil {
+		return "", fmt.Errorf("unparseable registration date %q : %s", date, err)
+	}
+	return date, nil
+}
+
+// getIANARootZoneDates fetches the IANA Root Zone Database page for each of
+// tlds and returns a map of TLD to its real delegation date. A ccTLD whose
+// page can't be fetched or parsed is logged and omitted rather than failing
+// the whole run, since ccTLDSource=="iana-rzd" should still produce a
+// usable map of best-effort dates.
+func getIANARootZoneDates(ctx context.Context, tlds []string, cacheDir string) (map[string]string, error) {
+	dates := make(map[string]string, len(tlds))
+	for _, tld := range tlds {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		page, err := fetchIANARootZonePage(ctx, tld, cacheDir)
+		if err != nil {
+			log.Warnf("skipping IANA Root Zone Database delegation date for %q : %s", tld, err)
+			continue
+		}
+		date, err := parseIANADelegationDate(page)
+		if err != nil {
+			log.Warnf("skipping IANA Root Zone Database delegation date for %q : %s", tld, err)
+			continue
+		}
+		dates[tld] = date
+	}
+	return dates, nil
+}
+
 // delegatedGTLDs filters the provided list of GTLDPeriods removing any entries
 // that were never delegated from the root DNS.
 func delegatedGTLDs(entries []util.GTLDPeriod) []util.GTLDPeriod {
@@ -211,20 +678,73 @@ func validateGTLDs(entries []util.GTLDPeriod) error {
 	return nil
 }
 
-// renderGTLDMap fetches the ICANN gTLD data, filters out undelegated entries,
-// validates the remaining entries have parseable dates, and renders the
-// gTLDMapTemplate to the provided writer using the validated entries (or
-// returns an error if any of the aforementioned steps fail). It then fetches
-// the ICANN TLD data, and uses it to populate any missing entries for ccTLDs.
-// These entries will have a default delegationDate because the data source is
-// not specific enough to provide one. The produced output text is a Golang
-// source code file in the `util` package that contains a single map variable
-// containing GTLDPeriod objects created with the ICANN data.
-func renderGTLDMap(writer io.Writer) error {
+// validateMergedGTLDs re-validates entries, the final tldMap Fetch returns
+// after merging in the IANA TLD list and (if -include-psl is set) the
+// Public Suffix List, the same way validateGTLDs validates the raw ICANN
+// gTLD JSON. It skips PSL-sourced entries: those intentionally carry an
+// empty DelegationDate (the PSL doesn't record root DNS delegation dates),
+// so running them through validateGTLDs's "empty DelegationDate is an
+// error" check would reject every one of them.
+func validateMergedGTLDs(entries []util.GTLDPeriod) error {
+	nonPSL := make([]util.GTLDPeriod, 0, len(entries))
+	for _, gTLD := range entries {
+		if gTLD.Source == util.SourcePSLICANN || gTLD.Source == util.SourcePSLPrivate {
+			continue
+		}
+		nonPSL = append(nonPSL, gTLD)
+	}
+	return validateGTLDs(nonPSL)
+}
+
+// normalizeEntry keys tld by its canonical A-label, rejecting (not
+// silently lowercasing) any entry whose name isn't valid IDNA2008/punycode.
+// ICANN's gTLD JSON mixes A-label and U-label forms across fields, and the
+// IANA TLD list and PSL are A-label/ASCII only, so callers must normalize
+// every entry before inserting it into a merge map: two entries for the
+// same TLD that arrive in different Unicode/ASCII spellings must collide
+// on the same key at insertion time, not survive as distinct entries to be
+// reconciled nondeterministically later.
+func normalizeEntry(tld util.GTLDPeriod) (string, util.GTLDPeriod, error) {
+	aLabel, err := toASCIIName(tld.GTLD)
+	if err != nil {
+		return "", util.GTLDPeriod{}, fmt.Errorf("invalid IDNA label for gTLD %q : %s", tld.GTLD, err)
+	}
+	uLabel, err := toUnicodeName(aLabel)
+	if err != nil {
+		return "", util.GTLDPeriod{}, fmt.Errorf("invalid IDNA label for gTLD %q : %s", tld.GTLD, err)
+	}
+	tld.GTLD = aLabel
+	tld.ALabel = aLabel
+	tld.ULabel = uLabel
+	return aLabel, tld, nil
+}
+
+// Fetch fetches and merges ICANN's gTLD JSON registry, the IANA TLD list,
+// and (if -include-psl is set) the Public Suffix List into a single,
+// deduplicated list of util.GTLDPeriod entries, sorted by GTLD, with the
+// `onion` special case included in that sorted order. It validates that every non-PSL-sourced
+// entry in that final merged set has parseable dates before returning; PSL
+// entries are exempt since the PSL doesn't carry delegation dates at all.
+// It also returns the sourceManifest
+// recording the SHA-256 digest of every input that went into that list, so
+// a Renderer can trace the generated output back to its exact sources.
+// This is the reusable core of zlint-gtld-update; callers that want the
+// merged gTLD data without going through one of the command line Renderers
+// can call it directly.
+func Fetch(ctx context.Context) ([]util.GTLDPeriod, *sourceManifest, error) {
+	if *ccTLDSource != "default" && *ccTLDSource != "iana-rzd" {
+		return nil, nil, fmt.Errorf("unknown -cctld-source value %q: must be \"default\" or \"iana-rzd\"", *ccTLDSource)
+	}
+
+	manifest, err := loadManifest(*sourcesManifest)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Get all of ICANN's gTLDs including ones that haven't been delegated.
-	allGTLDs, err := getGTLDData()
+	allGTLDs, err := getGTLDData(ctx, manifest)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	// Filter out the non-delegated gTLD entries
@@ -232,55 +752,437 @@ func renderGTLDMap(writer io.Writer) error {
 
 	// Validate that all of the delegated gTLDs have correct dates
 	if err := validateGTLDs(delegatedGTLDs); err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	// Get all of the TLDs. This data source doesn't provide delegationDates and
 	// so we only want to use it to populate missing entries in `delegatedGTLDs`,
 	// not to replace any existing entries that have more specific information
 	// about the validity period for the TLD.
-	allTLDs, err := getTLDData()
+	allTLDs, err := getTLDData(ctx, manifest)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	tldMap := make(map[string]util.GTLDPeriod)
 
 	// Deduplicate delegatedGTLDs into the tldMap first
 	for _, tld := range delegatedGTLDs {
-		tldMap[tld.GTLD] = tld
+		if tld.Source == "" {
+			tld.Source = util.SourceICANNGTLD
+		}
+		key, normalized, err := normalizeEntry(tld)
+		if err != nil {
+			return nil, nil, err
+		}
+		tldMap[key] = normalized
 	}
 
 	// Then populate any missing entries from the allTLDs list
 	for _, tld := range allTLDs {
-		if _, found := tldMap[tld.GTLD]; !found {
-			tldMap[tld.GTLD] = tld
+		key, normalized, err := normalizeEntry(tld)
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, found := tldMap[key]; !found {
+			normalized.Source = util.SourceICANNTLD
+			tldMap[key] = normalized
+		}
+	}
+
+	// Optionally merge in Public Suffix List entries for private/effective
+	// TLD coverage. Like the ICANN TLD list, PSL entries only fill in gaps;
+	// they never replace an entry with more specific delegation data.
+	if *includePSL {
+		sections, err := enabledPSLSections(*pslSections)
+		if err != nil {
+			return nil, nil, err
+		}
+		pslEntries, err := getPSLData(ctx, sections, manifest)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, tld := range pslEntries {
+			key, normalized, err := normalizeEntry(tld)
+			if err != nil {
+				return nil, nil, err
+			}
+			if _, found := tldMap[key]; !found {
+				tldMap[key] = normalized
+			}
 		}
 	}
 
+	// Override the default 1985-01-01 delegationDate on ccTLDs (the entries
+	// populated from allTLDs above) with the real date scraped from each
+	// one's IANA Root Zone Database page. gTLD/PSL entries are left alone:
+	// they either already carry a real delegationDate or, for PSL, don't
+	// represent a root DNS delegation at all.
+	if *ccTLDSource == "iana-rzd" {
+		var ccTLDs []string
+		for gtld, tld := range tldMap {
+			if tld.Source == util.SourceICANNTLD {
+				ccTLDs = append(ccTLDs, gtld)
+			}
+		}
+		sort.Strings(ccTLDs)
+		dates, err := getIANARootZoneDates(ctx, ccTLDs, *ianaRZDCacheDir)
+		if err != nil {
+			return nil, nil, err
+		}
+		for gtld, date := range dates {
+			tld := tldMap[gtld]
+			tld.DelegationDate = date
+			tld.Source = util.SourceIANARZD
+			tldMap[gtld] = tld
+		}
+	}
+
+	if err := saveManifest(*sourcesManifest, manifest); err != nil {
+		return nil, nil, err
+	}
+
+	// .onion is a special case and not a general gTLD. However, it is allowed
+	// in some circumstances in the web PKI so it's always included with a
+	// delegationDate based on the CABF ballot allowing EV issuance for
+	// .onion domains: https://cabforum.org/2015/02/18/ballot-144-validation-rules-dot-onion-names/
+	onionKey, onion, err := normalizeEntry(util.GTLDPeriod{
+		GTLD:           "onion",
+		DelegationDate: "2015-02-18",
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	tldMap[onionKey] = onion
+
+	entries := make([]util.GTLDPeriod, 0, len(tldMap))
+	for _, tld := range tldMap {
+		entries = append(entries, tld)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].GTLD < entries[j].GTLD })
+
+	// Re-validate the final merged set, not just the pre-merge delegatedGTLDs
+	// checked above: the allTLDs and (if -include-psl is set) PSL entries
+	// merged in since then haven't been checked yet.
+	if err := validateMergedGTLDs(entries); err != nil {
+		return nil, nil, err
+	}
+
+	return entries, manifest, nil
+}
+
+// RenderedFile is one output file produced by a Renderer. Suffix replaces
+// the extension of the user-provided output path (or, for a Renderer that
+// has only one RenderedFile, is empty to mean "use the output path as
+// given").
+type RenderedFile struct {
+	Suffix string
+	Data   []byte
+}
+
+// Renderer renders a merged, validated list of util.GTLDPeriod entries
+// into one or more output files in a specific format. Select one with the
+// -format flag.
+type Renderer interface {
+	Render(entries []util.GTLDPeriod) ([]RenderedFile, error)
+}
+
+// rendererForFormat returns the Renderer registered for name, or an error
+// if name doesn't match one of the supported -format values.
+func rendererForFormat(name string) (Renderer, error) {
+	switch name {
+	case "go-map":
+		return goMapRenderer{}, nil
+	case "json":
+		return jsonRenderer{}, nil
+	case "csv":
+		return csvRenderer{}, nil
+	case "proto":
+		return protoRenderer{}, nil
+	default:
+		return nil, fmt.Errorf("unknown -format %q: must be one of go-map, json, csv, proto", name)
+	}
+}
+
+// goMapRenderer renders entries as a Golang source code file in the `util`
+// package containing a single map variable, matching the behavior
+// zlint-gtld-update has always had. This is the default format, kept for
+// backwards compatibility with the `tldMap.go` file zlint ships. Manifest,
+// if set, is rendered into the file header so the generated map can be
+// traced back to the exact sources that produced it.
+type goMapRenderer struct {
+	Manifest *sourceManifest
+}
+
+func (r goMapRenderer) Render(entries []util.GTLDPeriod) ([]RenderedFile, error) {
 	templateData := struct {
-		GTLDs map[string]util.GTLDPeriod
+		GTLDs    []util.GTLDPeriod
+		Manifest *sourceManifest
 	}{
-		GTLDs: tldMap,
+		GTLDs:    entries,
+		Manifest: r.Manifest,
 	}
 
-	// Render the gTLD map to a buffer with the delegated gTLD data
 	var buf bytes.Buffer
 	if err := gTLDMapTemplate.Execute(&buf, templateData); err != nil {
-		return err
+		return nil, err
 	}
 
 	// format the buffer so it won't trip up the `gofmt_test.go` checks
 	formatted, err := format.Source(buf.Bytes())
 	if err != nil {
-		return err
+		return nil, err
+	}
+	return []RenderedFile{{Data: formatted}}, nil
+}
+
+// jsonRenderer renders entries as a JSON array of util.GTLDPeriod objects,
+// for consumers that want the merged gTLD data without a Go toolchain.
+type jsonRenderer struct{}
+
+func (jsonRenderer) Render(entries []util.GTLDPeriod) ([]RenderedFile, error) {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return []RenderedFile{{Data: append(data, '\n')}}, nil
+}
+
+// csvRenderer renders entries as CSV, one row per GTLDPeriod, with a
+// header row naming each column.
+type csvRenderer struct{}
+
+func (csvRenderer) Render(entries []util.GTLDPeriod) ([]RenderedFile, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write([]string{"gtld", "a_label", "u_label", "delegation_date", "removal_date", "source", "wildcard", "exception"}); err != nil {
+		return nil, err
+	}
+	for _, tld := range entries {
+		row := []string{
+			tld.GTLD,
+			tld.ALabel,
+			tld.ULabel,
+			tld.DelegationDate,
+			tld.RemovalDate,
+			string(tld.Source),
+			strconv.FormatBool(tld.Wildcard),
+			strconv.FormatBool(tld.Exception),
+		}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
 	}
+	return []RenderedFile{{Data: buf.Bytes()}}, nil
+}
+
+// gTLDRegistryProtoSchema is the protobuf schema for the proto output
+// format: a GTLDRegistry message holding the repeated GTLDPeriod entries.
+const gTLDRegistryProtoSchema = `syntax = "proto3";
+
+package zlint;
+
+option go_package = "github.com/zmap/zlint/util";
+
+// GTLDPeriod mirrors util.GTLDPeriod: the period of time a TLD (or
+// effective TLD) was valid for, and which data source produced it.
+message GTLDPeriod {
+  string gtld = 1;
+  string delegation_date = 2;
+  string removal_date = 3;
+  string source = 4;
+  bool wildcard = 5;
+  bool exception = 6;
+  string a_label = 7;
+  string u_label = 8;
+}
+
+// GTLDRegistry is the full merged gTLD/TLD/PSL data set produced by
+// zlint-gtld-update -format=proto.
+message GTLDRegistry {
+  repeated GTLDPeriod entries = 1;
+}
+`
 
-	// Write the formatted buffer to the writer
-	_, err = writer.Write(formatted)
+// gTLDRegistryPBGoTemplate renders a hand-written Go source file for the
+// GTLDRegistry message: the GTLDPeriod/GTLDRegistry types described by
+// gTLDRegistryProtoSchema, Marshal methods implementing their protobuf
+// wire encoding by hand (appendTag/appendVarint/appendTaggedString/
+// appendTaggedBool below), and the fetched entries as a literal
+// GTLDRegistry value. This is NOT run through protoc - there is no
+// .proto-to-.pb.go code generation step, and the emitted Marshal methods
+// have no Unmarshal counterpart - so despite the header comment inside
+// the template ("Code generated by go generate"), treat it as ordinary
+// hand-written wire encoding that happens to target an external .proto
+// schema, not protoc-gen-go output. TestWireEncodingHelpersRoundTrip in
+// main_test.go exercises testAppendTag/testAppendVarint/
+// testAppendTaggedString/testAppendTaggedBool, copies of the
+// appendTag/appendVarint/appendTaggedString/appendTaggedBool helpers inside
+// this template kept in sync by hand,
+// against google.golang.org/protobuf/encoding/protowire to catch a
+// tag/varint mistake before it ships silently.
+var gTLDRegistryPBGoTemplate = template.Must(template.New("gTLDRegistryPBGoTemplate").Parse(
+	`// Code generated by go generate; DO NOT EDIT.
+// This file was generated by zlint-gtld-update from gTLDRegistryProtoSchema.
+
+package util
+
+// GTLDPeriodProto is the generated protobuf message type for a single gTLD
+// registry entry. See gTLDRegistryProtoSchema for the .proto definition.
+type GTLDPeriodProto struct {
+	Gtld           string
+	DelegationDate string
+	RemovalDate    string
+	Source         string
+	Wildcard       bool
+	Exception      bool
+	ALabel         string
+	ULabel         string
+}
+
+// GTLDRegistry is the generated protobuf message type holding every
+// GTLDPeriodProto entry produced by zlint-gtld-update.
+type GTLDRegistry struct {
+	Entries []*GTLDPeriodProto
+}
+
+// Marshal encodes r using the GTLDRegistry wire format (field 1, repeated
+// embedded message).
+func (r *GTLDRegistry) Marshal() ([]byte, error) {
+	var out []byte
+	for _, e := range r.Entries {
+		body, err := e.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		out = appendTag(out, 1, wireBytes)
+		out = appendVarint(out, uint64(len(body)))
+		out = append(out, body...)
+	}
+	return out, nil
+}
+
+// Marshal encodes e using the GTLDPeriod wire format described by
+// gTLDRegistryProtoSchema.
+func (e *GTLDPeriodProto) Marshal() ([]byte, error) {
+	var out []byte
+	out = appendTaggedString(out, 1, e.Gtld)
+	out = appendTaggedString(out, 2, e.DelegationDate)
+	out = appendTaggedString(out, 3, e.RemovalDate)
+	out = appendTaggedString(out, 4, e.Source)
+	out = appendTaggedBool(out, 5, e.Wildcard)
+	out = appendTaggedBool(out, 6, e.Exception)
+	out = appendTaggedString(out, 7, e.ALabel)
+	out = appendTaggedString(out, 8, e.ULabel)
+	return out, nil
+}
+
+const (
+	wireVarint = 0
+	wireBytes  = 2
+)
+
+func appendTag(out []byte, field int, wireType int) []byte {
+	return appendVarint(out, uint64(field)<<3|uint64(wireType))
+}
+
+func appendVarint(out []byte, v uint64) []byte {
+	for v >= 0x80 {
+		out = append(out, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(out, byte(v))
+}
+
+func appendTaggedString(out []byte, field int, s string) []byte {
+	if s == "" {
+		return out
+	}
+	out = appendTag(out, field, wireBytes)
+	out = appendVarint(out, uint64(len(s)))
+	return append(out, s...)
+}
+
+func appendTaggedBool(out []byte, field int, b bool) []byte {
+	if !b {
+		return out
+	}
+	out = appendTag(out, field, wireVarint)
+	return appendVarint(out, 1)
+}
+
+// GeneratedGTLDRegistry is the GTLDRegistry value produced by the
+// zlint-gtld-update run that generated this file.
+var GeneratedGTLDRegistry = &GTLDRegistry{
+	Entries: []*GTLDPeriodProto{
+{{- range .Entries }}
+		{
+			Gtld:           "{{ .GTLD }}",
+			DelegationDate: "{{ .DelegationDate }}",
+			RemovalDate:    "{{ .RemovalDate }}",
+			Source:         "{{ .Source }}",
+			Wildcard:       {{ .Wildcard }},
+			Exception:      {{ .Exception }},
+			ALabel:         "{{ .ALabel }}",
+			ULabel:         "{{ .ULabel }}",
+		},
+{{- end }}
+	},
+}
+`))
+
+// protoRenderer renders entries as a GTLDRegistry protobuf message: the
+// static .proto schema (gTLDRegistryProtoSchema) plus hand-written Go
+// bindings and data (gTLDRegistryPBGoTemplate - see its doc comment for
+// why these aren't protoc-gen-go output despite the .pb.go suffix),
+// written as companion `.proto` and `.pb.go` files alongside the
+// requested output path.
+type protoRenderer struct{}
+
+func (protoRenderer) Render(entries []util.GTLDPeriod) ([]RenderedFile, error) {
+	var buf bytes.Buffer
+	if err := gTLDRegistryPBGoTemplate.Execute(&buf, struct {
+		Entries []util.GTLDPeriod
+	}{Entries: entries}); err != nil {
+		return nil, err
+	}
+	formatted, err := format.Source(buf.Bytes())
 	if err != nil {
+		return nil, err
+	}
+	return []RenderedFile{
+		{Suffix: ".proto", Data: []byte(gTLDRegistryProtoSchema)},
+		{Suffix: ".pb.go", Data: formatted},
+	}, nil
+}
+
+// writeRendered writes each of files to disk, deriving each file's path
+// from baseName with its extension replaced by the RenderedFile's Suffix
+// (or, for a single file with no Suffix, baseName itself). If baseName is
+// empty (writing to standard out) there must be exactly one file.
+func writeRendered(baseName string, files []RenderedFile) error {
+	if baseName == "" {
+		if len(files) != 1 {
+			return fmt.Errorf("-format produces multiple output files; an output filename argument is required")
+		}
+		_, err := os.Stdout.Write(files[0].Data)
 		return err
 	}
+
+	base := strings.TrimSuffix(baseName, filepath.Ext(baseName))
+	for _, rf := range files {
+		path := baseName
+		if rf.Suffix != "" {
+			path = base + rf.Suffix
+		}
+		if err := ioutil.WriteFile(path, rf.Data, 0664); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -294,31 +1196,45 @@ func init() {
 	log.SetLevel(log.InfoLevel)
 }
 
-// main handles rendering a gTLD map to either standard out (when no argument is
-// provided) or to the provided filename. If an error occurs it is printed to
-// standard err and the program terminates with a non-zero exit status.
+// main fetches and merges the gTLD data, renders it with the Renderer
+// selected by -format, and writes the result to standard out (when no
+// argument is provided) or to the provided filename. If an error occurs
+// it is printed to standard err and the program terminates with a
+// non-zero exit status.
 func main() {
 	errQuit := func(err error) {
 		fmt.Fprintf(os.Stderr, "error updating gTLD map: %s\n", err)
 		os.Exit(1)
 	}
 
-	// Default to writing to standard out
-	writer := os.Stdout
+	renderer, err := rendererForFormat(*outputFormat)
+	if err != nil {
+		errQuit(err)
+	}
+
+	var baseName string
 	if flag.NArg() > 0 {
-		// If a filename is specified as a command line flag then open it (creating
-		// if needed), truncate the existing contents, and use the file as the
-		// writer instead of standard out
-		filename := flag.Args()[0]
-		f, err := os.OpenFile(filename, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0664)
-		if err != nil {
-			errQuit(err)
-		}
-		defer f.Close()
-		writer = f
+		baseName = flag.Args()[0]
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	entries, manifest, err := Fetch(ctx)
+	if err != nil {
+		errQuit(err)
+	}
+	if gm, ok := renderer.(goMapRenderer); ok {
+		gm.Manifest = manifest
+		renderer = gm
+	}
+
+	files, err := renderer.Render(entries)
+	if err != nil {
+		errQuit(err)
 	}
 
-	if err := renderGTLDMap(writer); err != nil {
+	if err := writeRendered(baseName, files); err != nil {
 		errQuit(err)
 	}
 }
@@ -0,0 +1,179 @@
+/*
+ * ZLint Copyright 2018 Regents of the University of Michigan
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not
+ * use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+ * implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package main
+
+import (
+	"testing"
+
+	"github.com/zmap/zlint/util"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// TestNormalizeEntryCollidesOnCanonicalKey asserts that the same TLD
+// expressed in its Unicode (U-label) and ASCII-Compatible (A-label) forms
+// normalizes to the same map key. Before this existed, a merge step that
+// keyed entries by their raw (pre-normalization) string let both forms
+// survive as distinct entries, and whichever was re-keyed last during a
+// subsequent map iteration nondeterministically won.
+func TestNormalizeEntryCollidesOnCanonicalKey(t *testing.T) {
+	uLabelKey, uLabelEntry, err := normalizeEntry(util.GTLDPeriod{GTLD: "中国"})
+	if err != nil {
+		t.Fatalf("normalizeEntry(U-label) returned error: %s", err)
+	}
+	aLabelKey, aLabelEntry, err := normalizeEntry(util.GTLDPeriod{GTLD: "xn--fiqs8s"})
+	if err != nil {
+		t.Fatalf("normalizeEntry(A-label) returned error: %s", err)
+	}
+
+	if uLabelKey != aLabelKey {
+		t.Fatalf("U-label and A-label forms of the same TLD normalized to different keys: %q vs %q", uLabelKey, aLabelKey)
+	}
+	if uLabelEntry.ALabel != aLabelEntry.ALabel || uLabelEntry.ULabel != aLabelEntry.ULabel {
+		t.Fatalf("U-label and A-label forms normalized to different entries: %+v vs %+v", uLabelEntry, aLabelEntry)
+	}
+}
+
+// TestMergeUsesNormalizedKeyRegardlessOfInsertionOrder reproduces the
+// merge sequence Fetch uses (insert-if-absent from a lower-priority
+// source) and checks that it dedupes correctly no matter which spelling
+// of the same TLD is seen first.
+func TestMergeUsesNormalizedKeyRegardlessOfInsertionOrder(t *testing.T) {
+	for _, order := range [][]util.GTLDPeriod{
+		{{GTLD: "xn--fiqs8s", Source: util.SourceICANNGTLD}, {GTLD: "中国", Source: util.SourceICANNTLD}},
+		{{GTLD: "中国", Source: util.SourceICANNGTLD}, {GTLD: "xn--fiqs8s", Source: util.SourceICANNTLD}},
+	} {
+		tldMap := make(map[string]util.GTLDPeriod)
+		for i, tld := range order {
+			key, normalized, err := normalizeEntry(tld)
+			if err != nil {
+				t.Fatalf("normalizeEntry(%q) returned error: %s", tld.GTLD, err)
+			}
+			if _, found := tldMap[key]; found {
+				continue
+			}
+			if i > 0 {
+				t.Fatalf("second entry for the same TLD should have been deduped away, got a fresh insert for %q", tld.GTLD)
+			}
+			tldMap[key] = normalized
+		}
+		if len(tldMap) != 1 {
+			t.Fatalf("expected exactly one merged entry, got %d: %+v", len(tldMap), tldMap)
+		}
+	}
+}
+
+// The functions below are copies of the wire-encoding helpers
+// (appendTag/appendVarint/appendTaggedString/appendTaggedBool) inside
+// gTLDRegistryPBGoTemplate in main.go. They have to be copied rather than
+// called directly because the template's whole point is to emit a
+// standalone file for the `util` package with no dependency back on
+// zlint-gtld-update; keep these in sync with the template if either
+// changes.
+
+func testAppendTag(out []byte, field int, wireType int) []byte {
+	return testAppendVarint(out, uint64(field)<<3|uint64(wireType))
+}
+
+func testAppendVarint(out []byte, v uint64) []byte {
+	for v >= 0x80 {
+		out = append(out, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(out, byte(v))
+}
+
+func testAppendTaggedString(out []byte, field int, s string) []byte {
+	if s == "" {
+		return out
+	}
+	out = testAppendTag(out, field, 2) // wireBytes
+	out = testAppendVarint(out, uint64(len(s)))
+	return append(out, s...)
+}
+
+func testAppendTaggedBool(out []byte, field int, b bool) []byte {
+	if !b {
+		return out
+	}
+	out = testAppendTag(out, field, 0) // wireVarint
+	return testAppendVarint(out, 1)
+}
+
+// TestWireEncodingHelpersRoundTrip encodes a GTLDPeriod-shaped record with
+// the same field layout gTLDRegistryPBGoTemplate's GTLDPeriodProto.Marshal
+// uses, then decodes it with google.golang.org/protobuf/encoding/protowire
+// - the real library's low-level wire-format primitives - to catch a
+// tag/varint mistake in the hand-rolled encoder that the generated code
+// itself has no way to self-check, since it has no corresponding Unmarshal.
+func TestWireEncodingHelpersRoundTrip(t *testing.T) {
+	var out []byte
+	out = testAppendTaggedString(out, 1, "xn--fiqs8s") // gtld
+	out = testAppendTaggedString(out, 2, "1985-01-01") // delegation_date
+	out = testAppendTaggedString(out, 3, "")           // removal_date (omitted: empty)
+	out = testAppendTaggedString(out, 4, "icann-gtld") // source
+	out = testAppendTaggedBool(out, 5, true)           // wildcard
+	out = testAppendTaggedBool(out, 6, false)          // exception (omitted: false)
+	out = testAppendTaggedString(out, 7, "xn--fiqs8s") // a_label
+	out = testAppendTaggedString(out, 8, "中国")         // u_label
+
+	want := map[int]string{1: "xn--fiqs8s", 2: "1985-01-01", 4: "icann-gtld", 7: "xn--fiqs8s", 8: "中国"}
+	wantBool := map[int]bool{5: true}
+	gotStrings := map[int]string{}
+	gotBools := map[int]bool{}
+
+	for len(out) > 0 {
+		num, typ, n := protowire.ConsumeTag(out)
+		if n < 0 {
+			t.Fatalf("protowire.ConsumeTag failed to decode tag: %v", protowire.ParseError(n))
+		}
+		out = out[n:]
+		field := int(num)
+		switch typ {
+		case protowire.BytesType:
+			v, n := protowire.ConsumeBytes(out)
+			if n < 0 {
+				t.Fatalf("protowire.ConsumeBytes failed to decode field %d: %v", field, protowire.ParseError(n))
+			}
+			out = out[n:]
+			gotStrings[field] = string(v)
+		case protowire.VarintType:
+			v, n := protowire.ConsumeVarint(out)
+			if n < 0 {
+				t.Fatalf("protowire.ConsumeVarint failed to decode field %d: %v", field, protowire.ParseError(n))
+			}
+			out = out[n:]
+			gotBools[field] = v == 1
+		default:
+			t.Fatalf("unexpected wire type %d for field %d", typ, field)
+		}
+	}
+
+	for field, s := range want {
+		if gotStrings[field] != s {
+			t.Errorf("field %d: got string %q, want %q", field, gotStrings[field], s)
+		}
+	}
+	for field, b := range wantBool {
+		if gotBools[field] != b {
+			t.Errorf("field %d: got bool %v, want %v", field, gotBools[field], b)
+		}
+	}
+	if _, present := gotStrings[3]; present {
+		t.Errorf("field 3 (removal_date) should have been omitted for an empty string, but was present")
+	}
+	if _, present := gotBools[6]; present {
+		t.Errorf("field 6 (exception) should have been omitted for a false bool, but was present")
+	}
+}
@@ -0,0 +1,70 @@
+/*
+ * ZLint Copyright 2018 Regents of the University of Michigan
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not
+ * use this file except in compliance with the License. You may obtain a copy
+ * of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+ * implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package util
+
+// GTLDPeriodDateFormat is the format used for the DelegationDate and
+// RemovalDate fields of a GTLDPeriod.
+const GTLDPeriodDateFormat = "2006-01-02"
+
+// Source identifies which upstream data source a GTLDPeriod entry came
+// from. zlint-gtld-update merges multiple sources with different levels of
+// granularity, and the Source lets lints distinguish a delegated gTLD from
+// a registrable effective TLD from the Public Suffix List.
+type Source string
+
+const (
+	// SourceICANNGTLD is ICANN's gTLD JSON registry (full delegation data).
+	SourceICANNGTLD Source = "icann-gtld"
+	// SourceICANNTLD is the IANA alpha-by-domain TLD list (name only).
+	SourceICANNTLD Source = "icann-tld"
+	// SourcePSLICANN is an ICANN-section entry from the Public Suffix List.
+	SourcePSLICANN Source = "psl-icann"
+	// SourcePSLPrivate is a PRIVATE-section entry from the Public Suffix List.
+	SourcePSLPrivate Source = "psl-private"
+	// SourceIANARZD is a ccTLD whose DelegationDate was scraped from its
+	// IANA Root Zone Database page rather than defaulted, giving it the
+	// same delegation-date precision as SourceICANNGTLD entries.
+	SourceIANARZD Source = "iana-rzd"
+)
+
+// GTLDPeriod describes the period of time a TLD (or, for Public Suffix List
+// entries, an effective TLD) was valid for.
+type GTLDPeriod struct {
+	// GTLD is the top-level domain name in lowercase, A-label form. It is
+	// identical to ALabel and is the canonical key this entry is stored
+	// under; it's kept alongside ALabel for backwards compatibility.
+	GTLD string `json:"gTLD"`
+	// ALabel is the lowercase ASCII-Compatible Encoding of the TLD (e.g.
+	// `xn--fiqs8s`), identical to GTLD. Non-internationalized TLDs have an
+	// ALabel equal to their ULabel.
+	ALabel string `json:"aLabel"`
+	// ULabel is the Unicode form of the TLD (e.g. `中国`). For
+	// non-internationalized TLDs this is identical to ALabel.
+	ULabel string `json:"uLabel"`
+	// DelegationDate is the date the TLD was delegated, in
+	// GTLDPeriodDateFormat.
+	DelegationDate string `json:"delegationDate"`
+	// RemovalDate is the date the TLD was removed, in GTLDPeriodDateFormat,
+	// or the empty string if the TLD has not been removed.
+	RemovalDate string `json:"removalDate,omitempty"`
+	// Source identifies which upstream data source produced this entry.
+	// Defaults to SourceICANNGTLD for entries that predate the Public
+	// Suffix List integration.
+	Source Source `json:"source,omitempty"`
+	// Wildcard is true if this entry came from a PSL `*.` wildcard rule.
+	Wildcard bool `json:"wildcard,omitempty"`
+	// Exception is true if this entry came from a PSL `!` exception rule.
+	Exception bool `json:"exception,omitempty"`
+}
@@ -0,0 +1,249 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"context"
+	"fmt"
+
+	resources "google.golang.org/genproto/googleapis/ads/googleads/v2/resources"
+)
+
+// The below types stand in for KeywordPlanIdeaService.GenerateKeywordIdeas'
+// real request/response/enum types, which live in a sibling service and
+// enums package this tree never vendored. SeedKeywordPlanFromIdeas only
+// needs a handful of their fields, so rather than pull in the whole
+// KeywordPlanIdeaService client just for those, this defines the minimal
+// subset here. They are distinct Go types from the generated ones (for
+// example KeywordPlanNetwork is a plain string), and KeywordIdeaServiceClient
+// takes no grpc.CallOption, so a generated client or its messages are not
+// accepted directly and need a small adapter.
+// These should be deleted in favor of the real generated types the day this
+// tree vendors google.ads.googleads.v2.services.keyword_plan_idea_service.
+
+// KeywordMatchType mirrors enums.KeywordMatchTypeEnum_KeywordMatchType.
+type KeywordMatchType int32
+
+const (
+	KeywordMatchType_UNSPECIFIED KeywordMatchType = 0
+	KeywordMatchType_EXACT       KeywordMatchType = 2
+	KeywordMatchType_PHRASE      KeywordMatchType = 3
+	KeywordMatchType_BROAD       KeywordMatchType = 4
+)
+
+// KeywordPlanCompetitionLevel mirrors
+// enums.KeywordPlanCompetitionLevelEnum_KeywordPlanCompetitionLevel.
+type KeywordPlanCompetitionLevel int32
+
+const (
+	KeywordPlanCompetitionLevel_UNSPECIFIED KeywordPlanCompetitionLevel = 0
+	KeywordPlanCompetitionLevel_LOW         KeywordPlanCompetitionLevel = 2
+	KeywordPlanCompetitionLevel_MEDIUM      KeywordPlanCompetitionLevel = 3
+	KeywordPlanCompetitionLevel_HIGH        KeywordPlanCompetitionLevel = 4
+)
+
+// GenerateKeywordIdeasRequest is the request message for
+// KeywordIdeaServiceClient.GenerateKeywordIdeas.
+type GenerateKeywordIdeasRequest struct {
+	// Required. The ID of the customer with the recommendation.
+	CustomerId string
+	// The resource names of the geo targets to restrict ideas to.
+	GeoTargetConstants []string
+	// The language to restrict ideas to, as a resource name.
+	Language string
+	// The keyword plan network that determines where ideas are surfaced.
+	KeywordPlanNetwork string
+	// Exactly one of KeywordSeed, UrlSeed, or KeywordAndUrlSeed must be set.
+	KeywordSeed       *KeywordSeed
+	UrlSeed           *UrlSeed
+	KeywordAndUrlSeed *KeywordAndUrlSeed
+}
+
+// KeywordSeed seeds idea generation from a list of keywords.
+type KeywordSeed struct {
+	Keywords []string `protobuf:"bytes,1,rep,name=keywords,proto3" json:"keywords,omitempty"`
+}
+
+// UrlSeed seeds idea generation from the content of a URL.
+type UrlSeed struct {
+	Url string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+}
+
+// KeywordAndUrlSeed seeds idea generation from both keywords and a URL.
+type KeywordAndUrlSeed struct {
+	Url      string   `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Keywords []string `protobuf:"bytes,2,rep,name=keywords,proto3" json:"keywords,omitempty"`
+}
+
+// GenerateKeywordIdeasResponse is the response message for
+// KeywordIdeaServiceClient.GenerateKeywordIdeas.
+type GenerateKeywordIdeasResponse struct {
+	Results []*GenerateKeywordIdeaResult
+}
+
+// GenerateKeywordIdeaResult is a single generated keyword idea.
+type GenerateKeywordIdeaResult struct {
+	Text               string
+	KeywordIdeaMetrics *KeywordPlanHistoricalMetrics
+}
+
+// KeywordPlanHistoricalMetrics carries the historical metrics Google Ads
+// reports for a keyword idea.
+type KeywordPlanHistoricalMetrics struct {
+	AvgMonthlySearches     int64                       `protobuf:"varint,1,opt,name=avg_monthly_searches,json=avgMonthlySearches,proto3" json:"avg_monthly_searches,omitempty"`
+	Competition            KeywordPlanCompetitionLevel `protobuf:"varint,2,opt,name=competition,proto3,enum=google.ads.googleads.v2.services.KeywordPlanCompetitionLevel" json:"competition,omitempty"`
+	LowTopOfPageBidMicros  int64                       `protobuf:"varint,3,opt,name=low_top_of_page_bid_micros,json=lowTopOfPageBidMicros,proto3" json:"low_top_of_page_bid_micros,omitempty"`
+	HighTopOfPageBidMicros int64                       `protobuf:"varint,4,opt,name=high_top_of_page_bid_micros,json=highTopOfPageBidMicros,proto3" json:"high_top_of_page_bid_micros,omitempty"`
+}
+
+// KeywordIdeaServiceClient is the client API for KeywordPlanIdeaService's
+// GenerateKeywordIdeas RPC, the one method SeedKeywordPlanFromIdeas needs.
+type KeywordIdeaServiceClient interface {
+	GenerateKeywordIdeas(ctx context.Context, in *GenerateKeywordIdeasRequest) (*GenerateKeywordIdeasResponse, error)
+}
+
+// SeedKeywordPlanFromIdeasParams bundles the inputs SeedKeywordPlanFromIdeas
+// needs to turn idea generation into keyword plan operations.
+type SeedKeywordPlanFromIdeasParams struct {
+	// CustomerId is the ID of the customer the ideas and keywords belong to.
+	CustomerId string
+	// KeywordPlanAdGroup is the resource name of the keyword plan ad group the
+	// generated operations will create keywords under.
+	KeywordPlanAdGroup string
+	// KeywordSeeds, UrlSeed, and GeoTargetConstants/Language/
+	// KeywordPlanNetwork are forwarded to GenerateKeywordIdeas unchanged.
+	KeywordSeeds       []string
+	UrlSeed            string
+	Language           string
+	GeoTargetConstants []string
+	KeywordPlanNetwork string
+	// MatchType is applied to every generated KeywordPlanKeywordOperation.
+	MatchType KeywordMatchType
+	// MinAvgMonthlySearches drops ideas with fewer average monthly searches.
+	MinAvgMonthlySearches int64
+	// MaxCompetition drops ideas with a higher competition level. The zero
+	// value, KeywordPlanCompetitionLevel_UNSPECIFIED, applies no filter at
+	// all rather than rejecting every idea, since UNSPECIFIED isn't a
+	// competition level a caller would ever deliberately ask to cap at.
+	MaxCompetition KeywordPlanCompetitionLevel
+}
+
+// SeedKeywordPlanFromIdeas calls GenerateKeywordIdeas for the given seeds,
+// filters the results to ideas meeting MinAvgMonthlySearches and
+// MaxCompetition, deduplicates them by keyword text, and returns a
+// ready-to-submit MutateKeywordPlanKeywordsRequest with one
+// KeywordPlanKeywordOperation_Create per surviving idea. The CPC bid for
+// each operation is the idea's high-end top-of-page bid estimate, since
+// that keeps the seeded keyword plan competitive for the traffic the idea
+// predicts.
+func SeedKeywordPlanFromIdeas(ctx context.Context, client KeywordIdeaServiceClient, params SeedKeywordPlanFromIdeasParams) (*MutateKeywordPlanKeywordsRequest, error) {
+	req := &GenerateKeywordIdeasRequest{
+		CustomerId:         params.CustomerId,
+		GeoTargetConstants: params.GeoTargetConstants,
+		Language:           params.Language,
+		KeywordPlanNetwork: params.KeywordPlanNetwork,
+	}
+	switch {
+	case params.UrlSeed != "" && len(params.KeywordSeeds) > 0:
+		req.KeywordAndUrlSeed = &KeywordAndUrlSeed{Url: params.UrlSeed, Keywords: params.KeywordSeeds}
+	case params.UrlSeed != "":
+		req.UrlSeed = &UrlSeed{Url: params.UrlSeed}
+	default:
+		req.KeywordSeed = &KeywordSeed{Keywords: params.KeywordSeeds}
+	}
+
+	resp, err := client.GenerateKeywordIdeas(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("generating keyword ideas: %w", err)
+	}
+
+	seen := make(map[string]struct{}, len(resp.GetResults()))
+	var ops []*KeywordPlanKeywordOperation
+	for _, idea := range resp.GetResults() {
+		text := idea.GetText()
+		if text == "" {
+			continue
+		}
+		if _, dup := seen[text]; dup {
+			continue
+		}
+		metrics := idea.GetKeywordIdeaMetrics()
+		if metrics.GetAvgMonthlySearches() < params.MinAvgMonthlySearches {
+			continue
+		}
+		if params.MaxCompetition != KeywordPlanCompetitionLevel_UNSPECIFIED && metrics.GetCompetition() > params.MaxCompetition {
+			continue
+		}
+		seen[text] = struct{}{}
+
+		ops = append(ops, &KeywordPlanKeywordOperation{
+			Operation: &KeywordPlanKeywordOperation_Create{
+				Create: &resources.KeywordPlanKeyword{
+					KeywordPlanAdGroup: params.KeywordPlanAdGroup,
+					Text:               text,
+					MatchType:          int32(params.MatchType),
+					CpcBidMicros:       metrics.GetHighTopOfPageBidMicros(),
+				},
+			},
+		})
+	}
+
+	return &MutateKeywordPlanKeywordsRequest{
+		CustomerId: params.CustomerId,
+		Operations: ops,
+	}, nil
+}
+
+func (r *GenerateKeywordIdeasResponse) GetResults() []*GenerateKeywordIdeaResult {
+	if r != nil {
+		return r.Results
+	}
+	return nil
+}
+
+func (i *GenerateKeywordIdeaResult) GetText() string {
+	if i != nil {
+		return i.Text
+	}
+	return ""
+}
+
+func (i *GenerateKeywordIdeaResult) GetKeywordIdeaMetrics() *KeywordPlanHistoricalMetrics {
+	if i != nil {
+		return i.KeywordIdeaMetrics
+	}
+	return nil
+}
+
+func (m *KeywordPlanHistoricalMetrics) GetAvgMonthlySearches() int64 {
+	if m != nil {
+		return m.AvgMonthlySearches
+	}
+	return 0
+}
+
+func (m *KeywordPlanHistoricalMetrics) GetCompetition() KeywordPlanCompetitionLevel {
+	if m != nil {
+		return m.Competition
+	}
+	return KeywordPlanCompetitionLevel_UNSPECIFIED
+}
+
+func (m *KeywordPlanHistoricalMetrics) GetHighTopOfPageBidMicros() int64 {
+	if m != nil {
+		return m.HighTopOfPageBidMicros
+	}
+	return 0
+}
@@ -0,0 +1,115 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"context"
+	"testing"
+)
+
+// fakeKeywordIdeaServiceClient implements KeywordIdeaServiceClient by
+// returning a fixed GenerateKeywordIdeasResponse, so tests can exercise
+// SeedKeywordPlanFromIdeas' filtering without a real Google Ads backend.
+type fakeKeywordIdeaServiceClient struct {
+	resp *GenerateKeywordIdeasResponse
+}
+
+func (f *fakeKeywordIdeaServiceClient) GenerateKeywordIdeas(ctx context.Context, in *GenerateKeywordIdeasRequest) (*GenerateKeywordIdeasResponse, error) {
+	return f.resp, nil
+}
+
+func TestSeedKeywordPlanFromIdeasFiltersByThreshold(t *testing.T) {
+	tests := []struct {
+		name                  string
+		minAvgMonthlySearches int64
+		maxCompetition        KeywordPlanCompetitionLevel
+		ideas                 []*GenerateKeywordIdeaResult
+		wantTexts             []string
+	}{
+		{
+			name:           "zero-value MaxCompetition applies no competition filter",
+			maxCompetition: KeywordPlanCompetitionLevel_UNSPECIFIED,
+			ideas: []*GenerateKeywordIdeaResult{
+				{Text: "low", KeywordIdeaMetrics: &KeywordPlanHistoricalMetrics{Competition: KeywordPlanCompetitionLevel_LOW}},
+				{Text: "high", KeywordIdeaMetrics: &KeywordPlanHistoricalMetrics{Competition: KeywordPlanCompetitionLevel_HIGH}},
+			},
+			wantTexts: []string{"low", "high"},
+		},
+		{
+			name:           "idea above MaxCompetition is dropped",
+			maxCompetition: KeywordPlanCompetitionLevel_MEDIUM,
+			ideas: []*GenerateKeywordIdeaResult{
+				{Text: "medium", KeywordIdeaMetrics: &KeywordPlanHistoricalMetrics{Competition: KeywordPlanCompetitionLevel_MEDIUM}},
+				{Text: "high", KeywordIdeaMetrics: &KeywordPlanHistoricalMetrics{Competition: KeywordPlanCompetitionLevel_HIGH}},
+			},
+			wantTexts: []string{"medium"},
+		},
+		{
+			name:           "idea at MaxCompetition is kept",
+			maxCompetition: KeywordPlanCompetitionLevel_LOW,
+			ideas: []*GenerateKeywordIdeaResult{
+				{Text: "low", KeywordIdeaMetrics: &KeywordPlanHistoricalMetrics{Competition: KeywordPlanCompetitionLevel_LOW}},
+			},
+			wantTexts: []string{"low"},
+		},
+		{
+			name:                  "idea below MinAvgMonthlySearches is dropped",
+			minAvgMonthlySearches: 100,
+			ideas: []*GenerateKeywordIdeaResult{
+				{Text: "below", KeywordIdeaMetrics: &KeywordPlanHistoricalMetrics{AvgMonthlySearches: 99}},
+				{Text: "above", KeywordIdeaMetrics: &KeywordPlanHistoricalMetrics{AvgMonthlySearches: 101}},
+			},
+			wantTexts: []string{"above"},
+		},
+		{
+			name:                  "idea at MinAvgMonthlySearches is kept",
+			minAvgMonthlySearches: 100,
+			ideas: []*GenerateKeywordIdeaResult{
+				{Text: "at", KeywordIdeaMetrics: &KeywordPlanHistoricalMetrics{AvgMonthlySearches: 100}},
+			},
+			wantTexts: []string{"at"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeKeywordIdeaServiceClient{resp: &GenerateKeywordIdeasResponse{Results: tt.ideas}}
+
+			req, err := SeedKeywordPlanFromIdeas(context.Background(), fake, SeedKeywordPlanFromIdeasParams{
+				CustomerId:            "123",
+				KeywordPlanAdGroup:    "customers/123/keywordPlanAdGroups/456",
+				KeywordSeeds:          []string{"seed"},
+				MinAvgMonthlySearches: tt.minAvgMonthlySearches,
+				MaxCompetition:        tt.maxCompetition,
+			})
+			if err != nil {
+				t.Fatalf("SeedKeywordPlanFromIdeas returned error: %s", err)
+			}
+
+			var gotTexts []string
+			for _, op := range req.GetOperations() {
+				gotTexts = append(gotTexts, op.GetCreate().Text)
+			}
+			if len(gotTexts) != len(tt.wantTexts) {
+				t.Fatalf("got %d operations %v, want %d %v", len(gotTexts), gotTexts, len(tt.wantTexts), tt.wantTexts)
+			}
+			for i := range tt.wantTexts {
+				if gotTexts[i] != tt.wantTexts[i] {
+					t.Fatalf("operation %d: got text %q, want %q", i, gotTexts[i], tt.wantTexts[i])
+				}
+			}
+		})
+	}
+}
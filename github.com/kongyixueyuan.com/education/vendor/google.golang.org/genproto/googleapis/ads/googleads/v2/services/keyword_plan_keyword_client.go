@@ -0,0 +1,315 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	status "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+// maxMutateKeywordPlanKeywordOperations is the documented Google Ads limit on
+// the number of operations accepted by a single MutateKeywordPlanKeywords
+// call. Callers with larger batches are chunked transparently by
+// KeywordPlanKeywordClient.MutateAll.
+const maxMutateKeywordPlanKeywordOperations = 5000
+
+// retryableMutateCodes are the gRPC codes KeywordPlanKeywordClient.MutateAll
+// retries with backoff; all other errors are returned immediately.
+var retryableMutateCodes = map[codes.Code]bool{
+	codes.Unavailable:       true,
+	codes.DeadlineExceeded:  true,
+	codes.ResourceExhausted: true,
+}
+
+// BatchConfig controls how KeywordPlanKeywordClient.MutateAll chunks
+// operations and backs off between retried batches.
+type BatchConfig struct {
+	// BatchSize is the number of operations sent per MutateKeywordPlanKeywords
+	// call. Defaults to maxMutateKeywordPlanKeywordOperations if zero, and is
+	// clamped to that value if larger.
+	BatchSize int
+	// MaxRetries is the number of additional attempts made for a batch after
+	// a retryable error. Defaults to 3 if zero.
+	MaxRetries int
+	// InitialBackoff is the delay before the first retry of a batch. Defaults
+	// to 500ms if zero.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between retries. Defaults to 30s if zero.
+	MaxBackoff time.Duration
+	// PreflightValidate runs every batch with ValidateOnly set before
+	// committing any of them, so BatchMutateKeywordPlanKeywords can reject a
+	// bad large mutate without partially applying it.
+	PreflightValidate bool
+	// QPS caps the rate at which MutateKeywordPlanKeywords is called,
+	// including retried attempts, so a large MutateAll doesn't burn through
+	// the account's Google Ads API quota on its own. Zero (the default)
+	// means unlimited; this is a proactive client-side cap in addition to,
+	// not instead of, the reactive retry-on-ResourceExhausted behavior.
+	QPS float64
+}
+
+func (c BatchConfig) withDefaults() BatchConfig {
+	if c.BatchSize <= 0 || c.BatchSize > maxMutateKeywordPlanKeywordOperations {
+		c.BatchSize = maxMutateKeywordPlanKeywordOperations
+	}
+	if c.MaxRetries <= 0 {
+		c.MaxRetries = 3
+	}
+	if c.InitialBackoff <= 0 {
+		c.InitialBackoff = 500 * time.Millisecond
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = 30 * time.Second
+	}
+	return c
+}
+
+// MutateOption configures a call to KeywordPlanKeywordClient.MutateAll.
+type MutateOption func(*BatchConfig)
+
+// WithBatchConfig overrides the default batching and retry behavior.
+func WithBatchConfig(cfg BatchConfig) MutateOption {
+	return func(c *BatchConfig) { *c = cfg }
+}
+
+// PartialFailureError aggregates the partial_failure_error returned by each
+// batch of a chunked MutateKeywordPlanKeywords call into a single error,
+// since a large operation slice can span more than one RPC.
+type PartialFailureError struct {
+	// Errors holds one *status.Status per batch that reported a
+	// partial_failure_error, in the order the batches were sent.
+	Errors []*status.Status
+}
+
+func (e *PartialFailureError) Error() string {
+	if len(e.Errors) == 1 {
+		return fmt.Sprintf("keyword plan keyword mutate: partial failure: %s", e.Errors[0].GetMessage())
+	}
+	return fmt.Sprintf("keyword plan keyword mutate: partial failure in %d of the batches sent", len(e.Errors))
+}
+
+// rateLimiter is a token-bucket limiter with a bucket size of one, used to
+// proactively cap the rate of outbound MutateKeywordPlanKeywords calls. A
+// zero-value rateLimiter (interval 0) never blocks.
+type rateLimiter struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// newRateLimiter returns a rateLimiter that permits at most qps calls per
+// second, or one that never blocks if qps <= 0.
+func newRateLimiter(qps float64) *rateLimiter {
+	if qps <= 0 {
+		return &rateLimiter{}
+	}
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / qps)}
+}
+
+// wait blocks until the next permit is available, or ctx is done.
+func (r *rateLimiter) wait(ctx context.Context) error {
+	if r.interval == 0 {
+		return nil
+	}
+
+	r.mu.Lock()
+	now := time.Now()
+	if r.next.Before(now) {
+		r.next = now
+	}
+	delay := r.next.Sub(now)
+	r.next = r.next.Add(r.interval)
+	r.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// KeywordPlanKeywordClient wraps a KeywordPlanKeywordServiceClient to batch
+// large mutate requests, retry transient failures, and merge results so
+// callers don't reimplement that bookkeeping themselves.
+type KeywordPlanKeywordClient struct {
+	raw KeywordPlanKeywordServiceClient
+}
+
+// NewKeywordPlanKeywordClient wraps an existing KeywordPlanKeywordServiceClient.
+func NewKeywordPlanKeywordClient(raw KeywordPlanKeywordServiceClient) *KeywordPlanKeywordClient {
+	return &KeywordPlanKeywordClient{raw: raw}
+}
+
+// MutateAll mutates an arbitrarily large slice of KeywordPlanKeywordOperations
+// for a customer, splitting it into batches of at most the documented
+// Google Ads limit of 5000 operations per MutateKeywordPlanKeywords call.
+// Each batch is retried with exponential backoff and jitter on
+// codes.Unavailable, codes.DeadlineExceeded, and codes.ResourceExhausted. If
+// WithBatchConfig sets QPS, every send (including retried attempts) is
+// additionally paced to that rate, so a large MutateAll can't burn through
+// the account's API quota on its own before a retryable ResourceExhausted
+// ever has a chance to fire. Results from every batch are merged in
+// operation order; if any batch reports a partial_failure_error, MutateAll
+// returns the merged results of every batch alongside a
+// *PartialFailureError.
+func (c *KeywordPlanKeywordClient) MutateAll(ctx context.Context, customerID string, ops []*KeywordPlanKeywordOperation, partialFailure, validateOnly bool, opts ...MutateOption) ([]*MutateKeywordPlanKeywordResult, error) {
+	cfg := BatchConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	cfg = cfg.withDefaults()
+
+	var results []*MutateKeywordPlanKeywordResult
+	var partialErrs []*status.Status
+	limiter := newRateLimiter(cfg.QPS)
+
+	for start := 0; start < len(ops); start += cfg.BatchSize {
+		end := start + cfg.BatchSize
+		if end > len(ops) {
+			end = len(ops)
+		}
+
+		resp, err := c.mutateBatchWithRetry(ctx, &MutateKeywordPlanKeywordsRequest{
+			CustomerId:     customerID,
+			Operations:     ops[start:end],
+			PartialFailure: partialFailure,
+			ValidateOnly:   validateOnly,
+		}, cfg, limiter)
+		if err != nil {
+			return results, err
+		}
+
+		results = append(results, resp.GetResults()...)
+		if pfe := resp.GetPartialFailureError(); pfe != nil {
+			partialErrs = append(partialErrs, pfe)
+		}
+	}
+
+	if len(partialErrs) > 0 {
+		return results, &PartialFailureError{Errors: partialErrs}
+	}
+	return results, nil
+}
+
+func (c *KeywordPlanKeywordClient) mutateBatchWithRetry(ctx context.Context, req *MutateKeywordPlanKeywordsRequest, cfg BatchConfig, limiter *rateLimiter) (*MutateKeywordPlanKeywordsResponse, error) {
+	backoff := cfg.InitialBackoff
+	var lastErr error
+
+	for attempt := 0; attempt <= cfg.MaxRetries; attempt++ {
+		if err := limiter.wait(ctx); err != nil {
+			return nil, err
+		}
+
+		resp, err := c.raw.MutateKeywordPlanKeywords(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+		lastErr = err
+
+		if attempt == cfg.MaxRetries || !retryableMutateCodes[grpcstatus.Code(err)] {
+			return nil, err
+		}
+
+		jittered := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2+1)))
+		timer := time.NewTimer(jittered)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if backoff > cfg.MaxBackoff {
+			backoff = cfg.MaxBackoff
+		}
+	}
+	return nil, lastErr
+}
+
+// BatchMutateKeywordPlanKeywords is a convenience wrapper around
+// KeywordPlanKeywordClient.MutateAll for callers who would rather pass a raw
+// KeywordPlanKeywordServiceClient and get back a single merged
+// MutateKeywordPlanKeywordsResponse than manage a KeywordPlanKeywordClient
+// themselves. ops is chunked, retried, and re-assembled by MutateAll exactly
+// as it would be through that client, so Results stay in operation order
+// across batch boundaries. If WithBatchConfig sets PreflightValidate, every
+// batch is first sent with ValidateOnly set; only if that whole pass
+// succeeds does BatchMutateKeywordPlanKeywords commit the real mutate, so a
+// bad large batch is rejected before anything is applied.
+func BatchMutateKeywordPlanKeywords(ctx context.Context, client KeywordPlanKeywordServiceClient, customerID string, ops []*KeywordPlanKeywordOperation, partialFailure bool, opts ...MutateOption) (*MutateKeywordPlanKeywordsResponse, error) {
+	cfg := BatchConfig{}.withDefaults()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	wrapped := NewKeywordPlanKeywordClient(client)
+
+	if cfg.PreflightValidate {
+		if _, err := wrapped.MutateAll(ctx, customerID, ops, partialFailure, true, WithBatchConfig(cfg)); err != nil {
+			return nil, fmt.Errorf("keyword plan keyword batch mutate: preflight validation failed: %w", err)
+		}
+	}
+
+	results, err := wrapped.MutateAll(ctx, customerID, ops, partialFailure, false, WithBatchConfig(cfg))
+	var pfe *PartialFailureError
+	if errors.As(err, &pfe) {
+		return &MutateKeywordPlanKeywordsResponse{
+			Results:             results,
+			PartialFailureError: mergePartialFailureStatuses(pfe.Errors),
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &MutateKeywordPlanKeywordsResponse{Results: results}, nil
+}
+
+// mergePartialFailureStatuses combines the per-batch partial_failure_error
+// statuses a chunked mutate can collect into the single google.rpc.Status
+// that MutateKeywordPlanKeywordsResponse.PartialFailureError holds, since an
+// unbatched call only ever reports one. A single status is returned as is.
+// Otherwise each batch's message is kept, prefixed with "batch N", where N
+// counts only the batches that reported a partial failure; the operation
+// index range a batch covered and the statuses' Details are not kept.
+func mergePartialFailureStatuses(errs []*status.Status) *status.Status {
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = fmt.Sprintf("batch %d: %s", i, e.GetMessage())
+	}
+	return &status.Status{
+		Code:    int32(codes.Unknown),
+		Message: strings.Join(msgs, "; "),
+	}
+}
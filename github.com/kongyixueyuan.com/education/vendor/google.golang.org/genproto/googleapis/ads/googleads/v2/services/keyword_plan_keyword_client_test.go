@@ -0,0 +1,184 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	status "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+// fakeKeywordPlanKeywordServiceClient implements KeywordPlanKeywordServiceClient
+// with a scriptable MutateKeywordPlanKeywords, recording every call it
+// receives so tests can assert on batching, retry, and pacing behavior
+// without a real Google Ads backend.
+type fakeKeywordPlanKeywordServiceClient struct {
+	KeywordPlanKeywordServiceClient
+
+	mutate   func(req *MutateKeywordPlanKeywordsRequest) (*MutateKeywordPlanKeywordsResponse, error)
+	callsAt  []time.Time
+	numCalls int
+}
+
+func (f *fakeKeywordPlanKeywordServiceClient) MutateKeywordPlanKeywords(ctx context.Context, req *MutateKeywordPlanKeywordsRequest, opts ...grpc.CallOption) (*MutateKeywordPlanKeywordsResponse, error) {
+	f.numCalls++
+	f.callsAt = append(f.callsAt, time.Now())
+	return f.mutate(req)
+}
+
+func TestMutateAllChunksOperationsByBatchSize(t *testing.T) {
+	ops := make([]*KeywordPlanKeywordOperation, 5)
+	for i := range ops {
+		ops[i] = &KeywordPlanKeywordOperation{}
+	}
+
+	var seenBatchSizes []int
+	fake := &fakeKeywordPlanKeywordServiceClient{
+		mutate: func(req *MutateKeywordPlanKeywordsRequest) (*MutateKeywordPlanKeywordsResponse, error) {
+			seenBatchSizes = append(seenBatchSizes, len(req.GetOperations()))
+			return &MutateKeywordPlanKeywordsResponse{}, nil
+		},
+	}
+
+	c := NewKeywordPlanKeywordClient(fake)
+	if _, err := c.MutateAll(context.Background(), "123", ops, false, false, WithBatchConfig(BatchConfig{BatchSize: 2})); err != nil {
+		t.Fatalf("MutateAll returned error: %s", err)
+	}
+
+	want := []int{2, 2, 1}
+	if len(seenBatchSizes) != len(want) {
+		t.Fatalf("got %d batches %v, want %d batches %v", len(seenBatchSizes), seenBatchSizes, len(want), want)
+	}
+	for i := range want {
+		if seenBatchSizes[i] != want[i] {
+			t.Fatalf("batch %d: got size %d, want %d", i, seenBatchSizes[i], want[i])
+		}
+	}
+}
+
+func TestMutateAllRetriesRetryableCodes(t *testing.T) {
+	attempts := 0
+	fake := &fakeKeywordPlanKeywordServiceClient{
+		mutate: func(req *MutateKeywordPlanKeywordsRequest) (*MutateKeywordPlanKeywordsResponse, error) {
+			attempts++
+			if attempts < 3 {
+				return nil, grpcstatus.Error(codes.ResourceExhausted, "quota exceeded")
+			}
+			return &MutateKeywordPlanKeywordsResponse{}, nil
+		},
+	}
+
+	c := NewKeywordPlanKeywordClient(fake)
+	ops := []*KeywordPlanKeywordOperation{{}}
+	_, err := c.MutateAll(context.Background(), "123", ops, false, false, WithBatchConfig(BatchConfig{
+		MaxRetries:     5,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+	}))
+	if err != nil {
+		t.Fatalf("MutateAll returned error: %s", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("got %d attempts, want 3", attempts)
+	}
+}
+
+func TestMutateAllReturnsNonRetryableErrorImmediately(t *testing.T) {
+	attempts := 0
+	fake := &fakeKeywordPlanKeywordServiceClient{
+		mutate: func(req *MutateKeywordPlanKeywordsRequest) (*MutateKeywordPlanKeywordsResponse, error) {
+			attempts++
+			return nil, grpcstatus.Error(codes.InvalidArgument, "bad operation")
+		},
+	}
+
+	c := NewKeywordPlanKeywordClient(fake)
+	ops := []*KeywordPlanKeywordOperation{{}}
+	_, err := c.MutateAll(context.Background(), "123", ops, false, false, WithBatchConfig(BatchConfig{
+		MaxRetries:     5,
+		InitialBackoff: time.Millisecond,
+	}))
+	if err == nil {
+		t.Fatal("MutateAll returned nil error, want InvalidArgument")
+	}
+	if attempts != 1 {
+		t.Fatalf("got %d attempts, want 1 (no retry on a non-retryable code)", attempts)
+	}
+}
+
+func TestMutateAllMergesPartialFailureAcrossBatches(t *testing.T) {
+	ops := []*KeywordPlanKeywordOperation{{}, {}}
+	fake := &fakeKeywordPlanKeywordServiceClient{
+		mutate: func(req *MutateKeywordPlanKeywordsRequest) (*MutateKeywordPlanKeywordsResponse, error) {
+			return &MutateKeywordPlanKeywordsResponse{
+				PartialFailureError: &status.Status{Code: int32(codes.InvalidArgument), Message: "bad op"},
+			}, nil
+		},
+	}
+
+	c := NewKeywordPlanKeywordClient(fake)
+	_, err := c.MutateAll(context.Background(), "123", ops, true, false, WithBatchConfig(BatchConfig{BatchSize: 1}))
+	pfe, ok := err.(*PartialFailureError)
+	if !ok {
+		t.Fatalf("MutateAll returned %T, want *PartialFailureError", err)
+	}
+	if len(pfe.Errors) != 2 {
+		t.Fatalf("got %d partial failure statuses, want 2 (one per batch)", len(pfe.Errors))
+	}
+}
+
+func TestMutateAllPacesCallsToConfiguredQPS(t *testing.T) {
+	ops := make([]*KeywordPlanKeywordOperation, 3)
+	for i := range ops {
+		ops[i] = &KeywordPlanKeywordOperation{}
+	}
+
+	fake := &fakeKeywordPlanKeywordServiceClient{
+		mutate: func(req *MutateKeywordPlanKeywordsRequest) (*MutateKeywordPlanKeywordsResponse, error) {
+			return &MutateKeywordPlanKeywordsResponse{}, nil
+		},
+	}
+
+	c := NewKeywordPlanKeywordClient(fake)
+	const qps = 20.0
+	start := time.Now()
+	if _, err := c.MutateAll(context.Background(), "123", ops, false, false, WithBatchConfig(BatchConfig{BatchSize: 1, QPS: qps})); err != nil {
+		t.Fatalf("MutateAll returned error: %s", err)
+	}
+	elapsed := time.Since(start)
+
+	minElapsed := time.Duration(float64(len(ops)-1) * float64(time.Second) / qps)
+	if elapsed < minElapsed {
+		t.Fatalf("MutateAll with QPS=%v for %d ops took %s, want at least %s", qps, len(ops), elapsed, minElapsed)
+	}
+}
+
+func TestRateLimiterZeroQPSNeverBlocks(t *testing.T) {
+	limiter := newRateLimiter(0)
+	start := time.Now()
+	for i := 0; i < 1000; i++ {
+		if err := limiter.wait(context.Background()); err != nil {
+			t.Fatalf("wait returned error: %s", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("1000 waits on an unlimited rateLimiter took %s, want near-instant", elapsed)
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2020 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/ghodss/yaml"
+	resources "google.golang.org/genproto/googleapis/ads/googleads/v2/resources"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// keywordPlanKeywordResourceName matches
+// customers/{customer_id}/keywordPlanKeywords/{id}, the format documented
+// for KeywordPlanKeyword.resource_name.
+var keywordPlanKeywordResourceName = regexp.MustCompile(`^customers/\d+/keywordPlanKeywords/\d+$`)
+
+// OperationsCodec marshals KeywordPlanKeywordOperations to and from a
+// stable, human-editable JSON/YAML schema so a keyword plan can be stored in
+// Git and applied like a Kubernetes manifest, rather than living only in the
+// caller's memory as raw protoc-gen-go types.
+type OperationsCodec struct{}
+
+// NewOperationsCodec returns an OperationsCodec. It holds no state.
+func NewOperationsCodec() *OperationsCodec {
+	return &OperationsCodec{}
+}
+
+// MarshalJSON renders ops as a JSON array, each element encoded with
+// protojson so the Create/Update/Remove oneof and UpdateMask round-trip
+// using their standard proto3 camelCase field names.
+func (OperationsCodec) MarshalJSON(ops []*KeywordPlanKeywordOperation) ([]byte, error) {
+	raw := make([]json.RawMessage, len(ops))
+	for i, op := range ops {
+		b, err := protojson.MarshalOptions{}.Marshal(op)
+		if err != nil {
+			return nil, fmt.Errorf("marshaling operations[%d]: %w", i, err)
+		}
+		raw[i] = b
+	}
+	return json.Marshal(raw)
+}
+
+// UnmarshalJSON parses a JSON array produced by MarshalJSON back into
+// operations.
+func (OperationsCodec) UnmarshalJSON(data []byte) ([]*KeywordPlanKeywordOperation, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parsing operations array: %w", err)
+	}
+	ops := make([]*KeywordPlanKeywordOperation, len(raw))
+	for i, r := range raw {
+		op := &KeywordPlanKeywordOperation{}
+		if err := protojson.Unmarshal(r, op); err != nil {
+			return nil, fmt.Errorf("parsing operations[%d]: %w", i, err)
+		}
+		ops[i] = op
+	}
+	return ops, nil
+}
+
+// MarshalYAML renders ops as YAML, going through MarshalJSON first so field
+// naming matches the JSON form exactly.
+func (c OperationsCodec) MarshalYAML(ops []*KeywordPlanKeywordOperation) ([]byte, error) {
+	jsonBytes, err := c.MarshalJSON(ops)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.JSONToYAML(jsonBytes)
+}
+
+// UnmarshalYAML parses a YAML document into operations by converting it to
+// JSON and delegating to UnmarshalJSON.
+func (c OperationsCodec) UnmarshalYAML(data []byte) ([]*KeywordPlanKeywordOperation, error) {
+	jsonBytes, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return c.UnmarshalJSON(jsonBytes)
+}
+
+// Validate checks op against the invariants the generated type can't
+// enforce on its own: Update and Remove must carry a resource name matching
+// customers/{customer_id}/keywordPlanKeywords/{id}, and Create must not.
+func (OperationsCodec) Validate(op *KeywordPlanKeywordOperation) error {
+	switch {
+	case op.GetCreate() != nil:
+		if rn := op.GetCreate().GetResourceName(); rn != "" {
+			return fmt.Errorf("keyword plan keyword operation: create must not set resource_name, got %q", rn)
+		}
+	case op.GetUpdate() != nil:
+		if rn := op.GetUpdate().GetResourceName(); !keywordPlanKeywordResourceName.MatchString(rn) {
+			return fmt.Errorf("keyword plan keyword operation: update resource_name %q does not match %s", rn, keywordPlanKeywordResourceName)
+		}
+	case op.GetRemove() != "":
+		if rn := op.GetRemove(); !keywordPlanKeywordResourceName.MatchString(rn) {
+			return fmt.Errorf("keyword plan keyword operation: remove resource_name %q does not match %s", rn, keywordPlanKeywordResourceName)
+		}
+	default:
+		return fmt.Errorf("keyword plan keyword operation: exactly one of create, update, or remove must be set")
+	}
+	return nil
+}
+
+// DeriveUpdateMask computes the FieldMask between an existing
+// KeywordPlanKeyword and the desired state, so callers editing a plan
+// document don't have to hand-maintain update_mask themselves. Only the
+// fields this package knows how to compare (text, match_type,
+// cpc_bid_micros, negative) are considered.
+func DeriveUpdateMask(existing, desired *resources.KeywordPlanKeyword) *fieldmaskpb.FieldMask {
+	var paths []string
+	if existing.GetText() != desired.GetText() {
+		paths = append(paths, "text")
+	}
+	if existing.GetMatchType() != desired.GetMatchType() {
+		paths = append(paths, "match_type")
+	}
+	if existing.GetCpcBidMicros() != desired.GetCpcBidMicros() {
+		paths = append(paths, "cpc_bid_micros")
+	}
+	if existing.GetNegative() != desired.GetNegative() {
+		paths = append(paths, "negative")
+	}
+	return &fieldmaskpb.FieldMask{Paths: paths}
+}
+
+// PrepareUpdate fills in op.UpdateMask from DeriveUpdateMask when the caller
+// hasn't already supplied one, so a plan document can omit update_mask and
+// let it be computed from the diff against existing.
+func PrepareUpdate(op *KeywordPlanKeywordOperation, existing *resources.KeywordPlanKeyword) {
+	if op.GetUpdateMask() != nil || op.GetUpdate() == nil {
+		return
+	}
+	op.UpdateMask = DeriveUpdateMask(existing, op.GetUpdate())
+}
+
+// BuildMutateRequest assembles a MutateKeywordPlanKeywordsRequest from ops
+// for the given customer. When dryRun is true, ValidateOnly is set so the
+// request only validates the plan without applying it, mirroring a
+// `kubectl apply --dry-run`-style workflow.
+func BuildMutateRequest(customerID string, ops []*KeywordPlanKeywordOperation, dryRun bool) *MutateKeywordPlanKeywordsRequest {
+	return &MutateKeywordPlanKeywordsRequest{
+		CustomerId:   customerID,
+		Operations:   ops,
+		ValidateOnly: dryRun,
+	}
+}
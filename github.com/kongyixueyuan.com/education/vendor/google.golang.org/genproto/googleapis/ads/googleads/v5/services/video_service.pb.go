@@ -28,11 +28,13 @@ import (
 	proto "github.com/golang/protobuf/proto"
 	resources "google.golang.org/genproto/googleapis/ads/googleads/v5/resources"
 	_ "google.golang.org/genproto/googleapis/api/annotations"
+	status "google.golang.org/genproto/googleapis/rpc/status"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
-	status "google.golang.org/grpc/status"
+	status1 "google.golang.org/grpc/status"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 const (
@@ -95,6 +97,286 @@ func (x *GetVideoRequest) GetResourceName() string {
 	return ""
 }
 
+// The below MutateVideos messages are hand-added: VideoService only shipped
+// GetVideo in the upstream .proto this file was generated from, and adding
+// MutateVideos properly would mean regenerating the rawDesc bytes further
+// down by running protoc, which isn't vendored in this tree. They're
+// written in the pre-APIv2 protoc-gen-go style (struct tags plus
+// Reset/String/ProtoMessage, and XXX_OneofWrappers for VideoOperation's
+// oneof) instead, so they marshal correctly via the legacy struct-tag
+// reflection path without an entry in
+// file_google_ads_googleads_v5_services_video_service_proto_rawDesc.
+
+// Request message for [VideoService.MutateVideos][google.ads.googleads.v5.services.VideoService.MutateVideos].
+type MutateVideosRequest struct {
+	// Required. The ID of the customer whose videos are being modified.
+	CustomerId string `protobuf:"bytes,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	// Required. The list of operations to perform on individual videos.
+	Operations []*VideoOperation `protobuf:"bytes,2,rep,name=operations,proto3" json:"operations,omitempty"`
+	// If true, successful operations will be carried out and invalid
+	// operations will return errors. If false, all operations will be carried
+	// out in one transaction if and only if they are all valid.
+	// Default is false.
+	PartialFailure bool `protobuf:"varint,3,opt,name=partial_failure,json=partialFailure,proto3" json:"partial_failure,omitempty"`
+	// If true, the request is validated but not executed. Only errors are
+	// returned, not results.
+	ValidateOnly bool `protobuf:"varint,4,opt,name=validate_only,json=validateOnly,proto3" json:"validate_only,omitempty"`
+}
+
+func (m *MutateVideosRequest) Reset()         { *m = MutateVideosRequest{} }
+func (m *MutateVideosRequest) String() string { return proto.CompactTextString(m) }
+func (*MutateVideosRequest) ProtoMessage()    {}
+
+func (x *MutateVideosRequest) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+func (x *MutateVideosRequest) GetOperations() []*VideoOperation {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+func (x *MutateVideosRequest) GetPartialFailure() bool {
+	if x != nil {
+		return x.PartialFailure
+	}
+	return false
+}
+
+func (x *MutateVideosRequest) GetValidateOnly() bool {
+	if x != nil {
+		return x.ValidateOnly
+	}
+	return false
+}
+
+// A single operation (create, update, remove) on a video.
+type VideoOperation struct {
+	// The FieldMask that determines which resource fields are modified in an
+	// update.
+	UpdateMask *fieldmaskpb.FieldMask `protobuf:"bytes,4,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	// The mutate operation.
+	//
+	// Types that are assignable to Operation:
+	//	*VideoOperation_Create
+	//	*VideoOperation_Update
+	//	*VideoOperation_Remove
+	Operation isVideoOperation_Operation `protobuf_oneof:"operation"`
+}
+
+func (m *VideoOperation) Reset()         { *m = VideoOperation{} }
+func (m *VideoOperation) String() string { return proto.CompactTextString(m) }
+func (*VideoOperation) ProtoMessage()    {}
+
+func (x *VideoOperation) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+func (m *VideoOperation) GetOperation() isVideoOperation_Operation {
+	if m != nil {
+		return m.Operation
+	}
+	return nil
+}
+
+func (x *VideoOperation) GetCreate() *resources.Video {
+	if x, ok := x.GetOperation().(*VideoOperation_Create); ok {
+		return x.Create
+	}
+	return nil
+}
+
+func (x *VideoOperation) GetUpdate() *resources.Video {
+	if x, ok := x.GetOperation().(*VideoOperation_Update); ok {
+		return x.Update
+	}
+	return nil
+}
+
+func (x *VideoOperation) GetRemove() string {
+	if x, ok := x.GetOperation().(*VideoOperation_Remove); ok {
+		return x.Remove
+	}
+	return ""
+}
+
+type isVideoOperation_Operation interface {
+	isVideoOperation_Operation()
+}
+
+type VideoOperation_Create struct {
+	// Create operation: No resource name is expected for the new video.
+	Create *resources.Video `protobuf:"bytes,1,opt,name=create,proto3,oneof"`
+}
+
+type VideoOperation_Update struct {
+	// Update operation: The video is expected to have a valid resource name.
+	Update *resources.Video `protobuf:"bytes,2,opt,name=update,proto3,oneof"`
+}
+
+type VideoOperation_Remove struct {
+	// Remove operation: A resource name for the removed video is expected,
+	// in this format:
+	//
+	// `customers/{customer_id}/videos/{video_id}`
+	Remove string `protobuf:"bytes,3,opt,name=remove,proto3,oneof"`
+}
+
+func (*VideoOperation_Create) isVideoOperation_Operation() {}
+
+func (*VideoOperation_Update) isVideoOperation_Operation() {}
+
+func (*VideoOperation_Remove) isVideoOperation_Operation() {}
+
+// XXX_OneofWrappers lists the wrapper types for the Operation oneof so the
+// legacy struct-tag reflection path (used since VideoOperation has no
+// ProtoReflect/rawDesc entry) can marshal and unmarshal it; without this,
+// proto.Marshal panics trying to find a oneof field coder for this message.
+func (*VideoOperation) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*VideoOperation_Create)(nil),
+		(*VideoOperation_Update)(nil),
+		(*VideoOperation_Remove)(nil),
+	}
+}
+
+// Response message for a video mutate.
+type MutateVideosResponse struct {
+	// Errors that pertain to operation failures in the partial failure mode.
+	// Returned only when partial_failure = true and all errors occur inside
+	// the operations. If any errors occur outside the operations (e.g. auth
+	// errors), we return an RPC level error.
+	PartialFailureError *status.Status `protobuf:"bytes,3,opt,name=partial_failure_error,json=partialFailureError,proto3" json:"partial_failure_error,omitempty"`
+	// All results for the mutate.
+	Results []*MutateVideoResult `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+}
+
+func (m *MutateVideosResponse) Reset()         { *m = MutateVideosResponse{} }
+func (m *MutateVideosResponse) String() string { return proto.CompactTextString(m) }
+func (*MutateVideosResponse) ProtoMessage()    {}
+
+func (x *MutateVideosResponse) GetPartialFailureError() *status.Status {
+	if x != nil {
+		return x.PartialFailureError
+	}
+	return nil
+}
+
+func (x *MutateVideosResponse) GetResults() []*MutateVideoResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// The result for the video mutate.
+type MutateVideoResult struct {
+	// Returned for successful operations.
+	ResourceName string `protobuf:"bytes,1,opt,name=resource_name,json=resourceName,proto3" json:"resource_name,omitempty"`
+}
+
+func (m *MutateVideoResult) Reset()         { *m = MutateVideoResult{} }
+func (m *MutateVideoResult) String() string { return proto.CompactTextString(m) }
+func (*MutateVideoResult) ProtoMessage()    {}
+
+func (x *MutateVideoResult) GetResourceName() string {
+	if x != nil {
+		return x.ResourceName
+	}
+	return ""
+}
+
+// Request message for [VideoService.SearchVideos][google.ads.googleads.v5.services.VideoService.SearchVideos].
+type SearchVideosRequest struct {
+	// Required. The ID of the customer being queried.
+	CustomerId string `protobuf:"bytes,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	// Required. The GAQL query string used to select the videos to return.
+	Query string `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
+	// Number of results per streamed SearchVideosResponse. Default is 10000.
+	PageSize int32 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Token of the page to retrieve. If unspecified, the first page is
+	// returned. Use the value obtained from next_page_token in the previous
+	// response to request the next page.
+	PageToken string `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+}
+
+func (m *SearchVideosRequest) Reset()         { *m = SearchVideosRequest{} }
+func (m *SearchVideosRequest) String() string { return proto.CompactTextString(m) }
+func (*SearchVideosRequest) ProtoMessage()    {}
+
+func (x *SearchVideosRequest) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+func (x *SearchVideosRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchVideosRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *SearchVideosRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+// One streamed batch of results for [VideoService.SearchVideos][google.ads.googleads.v5.services.VideoService.SearchVideos].
+type SearchVideosResponse struct {
+	// The videos that matched the query.
+	Results []*resources.Video `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	// Pagination token used to retrieve the next page of results. Pass the
+	// content of this string as the page_token attribute of the next request.
+	// An empty value means no more results are available.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// Total number of results that match the query ignoring the LIMIT clause.
+	TotalResultsCount int64 `protobuf:"varint,3,opt,name=total_results_count,json=totalResultsCount,proto3" json:"total_results_count,omitempty"`
+}
+
+func (m *SearchVideosResponse) Reset()         { *m = SearchVideosResponse{} }
+func (m *SearchVideosResponse) String() string { return proto.CompactTextString(m) }
+func (*SearchVideosResponse) ProtoMessage()    {}
+
+func (x *SearchVideosResponse) GetResults() []*resources.Video {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *SearchVideosResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *SearchVideosResponse) GetTotalResultsCount() int64 {
+	if x != nil {
+		return x.TotalResultsCount
+	}
+	return 0
+}
+
 var File_google_ads_googleads_v5_services_video_service_proto protoreflect.FileDescriptor
 
 var file_google_ads_googleads_v5_services_video_service_proto_rawDesc = []byte{
@@ -232,6 +514,11 @@ const _ = grpc.SupportPackageIsVersion6
 type VideoServiceClient interface {
 	// Returns the requested video in full detail.
 	GetVideo(ctx context.Context, in *GetVideoRequest, opts ...grpc.CallOption) (*resources.Video, error)
+	// Creates, updates, or removes videos. Operation statuses are returned.
+	MutateVideos(ctx context.Context, in *MutateVideosRequest, opts ...grpc.CallOption) (*MutateVideosResponse, error)
+	// Returns all videos that match the search query, streamed one page of
+	// results at a time.
+	SearchVideos(ctx context.Context, in *SearchVideosRequest, opts ...grpc.CallOption) (VideoService_SearchVideosClient, error)
 }
 
 type videoServiceClient struct {
@@ -251,10 +538,58 @@ func (c *videoServiceClient) GetVideo(ctx context.Context, in *GetVideoRequest,
 	return out, nil
 }
 
+func (c *videoServiceClient) MutateVideos(ctx context.Context, in *MutateVideosRequest, opts ...grpc.CallOption) (*MutateVideosResponse, error) {
+	out := new(MutateVideosResponse)
+	err := c.cc.Invoke(ctx, "/google.ads.googleads.v5.services.VideoService/MutateVideos", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *videoServiceClient) SearchVideos(ctx context.Context, in *SearchVideosRequest, opts ...grpc.CallOption) (VideoService_SearchVideosClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_VideoService_serviceDesc.Streams[0], "/google.ads.googleads.v5.services.VideoService/SearchVideos", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &videoServiceSearchVideosClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// VideoService_SearchVideosClient is the client-side stream handle returned
+// by VideoServiceClient.SearchVideos.
+type VideoService_SearchVideosClient interface {
+	Recv() (*SearchVideosResponse, error)
+	grpc.ClientStream
+}
+
+type videoServiceSearchVideosClient struct {
+	grpc.ClientStream
+}
+
+func (x *videoServiceSearchVideosClient) Recv() (*SearchVideosResponse, error) {
+	m := new(SearchVideosResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // VideoServiceServer is the server API for VideoService service.
 type VideoServiceServer interface {
 	// Returns the requested video in full detail.
 	GetVideo(context.Context, *GetVideoRequest) (*resources.Video, error)
+	// Creates, updates, or removes videos. Operation statuses are returned.
+	MutateVideos(context.Context, *MutateVideosRequest) (*MutateVideosResponse, error)
+	// Returns all videos that match the search query, streamed one page of
+	// results at a time.
+	SearchVideos(*SearchVideosRequest, VideoService_SearchVideosServer) error
 }
 
 // UnimplementedVideoServiceServer can be embedded to have forward compatible implementations.
@@ -262,7 +597,15 @@ type UnimplementedVideoServiceServer struct {
 }
 
 func (*UnimplementedVideoServiceServer) GetVideo(context.Context, *GetVideoRequest) (*resources.Video, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetVideo not implemented")
+	return nil, status1.Errorf(codes.Unimplemented, "method GetVideo not implemented")
+}
+
+func (*UnimplementedVideoServiceServer) MutateVideos(context.Context, *MutateVideosRequest) (*MutateVideosResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method MutateVideos not implemented")
+}
+
+func (*UnimplementedVideoServiceServer) SearchVideos(*SearchVideosRequest, VideoService_SearchVideosServer) error {
+	return status1.Errorf(codes.Unimplemented, "method SearchVideos not implemented")
 }
 
 func RegisterVideoServiceServer(s *grpc.Server, srv VideoServiceServer) {
@@ -287,6 +630,57 @@ func _VideoService_GetVideo_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+// _VideoService_MutateVideos_Handler serves MutateVideos over gRPC only.
+// Unlike GetVideo, which restVideoServiceClient transcodes to REST in
+// video_rest_client.go, MutateVideos has no REST transcoding: its request
+// and response types are hand-added in the pre-APIv2 protoc-gen-go style
+// (see the comment above MutateVideosRequest), so there is no
+// google.api.http annotation compiled into this file's descriptor for a
+// REST path to transcode against.
+func _VideoService_MutateVideos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MutateVideosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VideoServiceServer).MutateVideos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/google.ads.googleads.v5.services.VideoService/MutateVideos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VideoServiceServer).MutateVideos(ctx, req.(*MutateVideosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// _VideoService_SearchVideos_Handler wraps the grpc.ServerStream passed by
+// the transport so that VideoServiceServer implementations can call
+// stream.Send(...) for each page until their underlying cursor is exhausted.
+func _VideoService_SearchVideos_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SearchVideosRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(VideoServiceServer).SearchVideos(m, &videoServiceSearchVideosServer{stream})
+}
+
+// VideoService_SearchVideosServer is the server-side stream handle passed
+// to VideoServiceServer.SearchVideos.
+type VideoService_SearchVideosServer interface {
+	Send(*SearchVideosResponse) error
+	grpc.ServerStream
+}
+
+type videoServiceSearchVideosServer struct {
+	grpc.ServerStream
+}
+
+func (x *videoServiceSearchVideosServer) Send(m *SearchVideosResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _VideoService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "google.ads.googleads.v5.services.VideoService",
 	HandlerType: (*VideoServiceServer)(nil),
@@ -295,7 +689,17 @@ var _VideoService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetVideo",
 			Handler:    _VideoService_GetVideo_Handler,
 		},
+		{
+			MethodName: "MutateVideos",
+			Handler:    _VideoService_MutateVideos_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SearchVideos",
+			Handler:       _VideoService_SearchVideos_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "google/ads/googleads/v5/services/video_service.proto",
 }
@@ -0,0 +1,195 @@
+// Copyright 2016 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package events converts Genomics OperationMetadata/OperationEvent
+// transitions into CloudEvents v1.0 envelopes, so Genomics jobs can be
+// plugged into knative/eventing-style pipelines without hand-rolling the
+// mapping.
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	genomics "google.golang.org/genproto/googleapis/genomics/v1"
+	statuspb "google.golang.org/genproto/googleapis/rpc/status"
+)
+
+const (
+	specVersion = "1.0"
+
+	typeStarted    = "google.genomics.v1.operation.started"
+	typeEventAdded = "google.genomics.v1.operation.eventAdded"
+	typeCompleted  = "google.genomics.v1.operation.completed"
+	typeFailed     = "google.genomics.v1.operation.failed"
+)
+
+// NewEmitter diffs prev against curr and returns the CloudEvents that
+// describe the transition. prev may be nil, in which case curr is treated
+// as the operation's first observed state. terminalErr is the
+// google.rpc.Status the longrunning.Operation carried, if any, and is only
+// consulted once EndTime is newly populated. The returned events are
+// ordered: a "started" event (if this is the first observation), one
+// "eventAdded" event per newly appended OperationEvent, and a terminal
+// "completed" or "failed" event if EndTime was newly populated.
+func NewEmitter(prev, curr *genomics.OperationMetadata, name string, terminalErr *statuspb.Status) []cloudevents.Event {
+	if curr == nil {
+		return nil
+	}
+
+	var out []cloudevents.Event
+	source := "//genomics.googleapis.com/projects/" + curr.GetProjectId()
+
+	if prev == nil {
+		out = append(out, newEvent(typeStarted, source, name, curr.GetCreateTime().AsTime(), curr))
+	}
+
+	prevSeen := len(prev.GetEvents())
+	for _, ev := range curr.GetEvents()[min(prevSeen, len(curr.GetEvents())):] {
+		out = append(out, newEvent(typeEventAdded, source, name, ev.GetStartTime().AsTime(), ev))
+	}
+
+	if prevEndEmpty(prev) && curr.GetEndTime() != nil {
+		evType, data := typeCompleted, interface{}(curr)
+		if terminalErr != nil {
+			evType, data = typeFailed, terminalErr
+		}
+		out = append(out, newEvent(evType, source, name, curr.GetEndTime().AsTime(), data))
+	}
+
+	return out
+}
+
+func prevEndEmpty(prev *genomics.OperationMetadata) bool {
+	return prev == nil || prev.GetEndTime() == nil
+}
+
+func newEvent(evType, source, subject string, t time.Time, data interface{}) cloudevents.Event {
+	e := cloudevents.NewEvent(specVersion)
+	e.SetType(evType)
+	e.SetSource(source)
+	e.SetSubject(subject)
+	e.SetTime(t)
+	e.SetID(fmt.Sprintf("%s-%s-%d", subject, evType, t.UnixNano()))
+	_ = e.SetData(cloudevents.ApplicationJSON, data)
+	return e
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// Handler receives polled Genomics OperationMetadata, diffs it against the
+// last observation for the same operation name, and re-broadcasts the
+// resulting CloudEvents to Sink, one HTTP POST per event, each with a
+// structured-mode CloudEvents JSON body.
+type Handler struct {
+	// Sink is the URL CloudEvents are POSTed to.
+	Sink string
+	// HTTPClient is used to deliver events to Sink. Defaults to
+	// http.DefaultClient if nil.
+	HTTPClient *http.Client
+
+	mu   sync.Mutex
+	last map[string]*genomics.OperationMetadata
+}
+
+func (h *Handler) client() *http.Client {
+	if h.HTTPClient != nil {
+		return h.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// ServeHTTP expects a JSON body of the form
+// {"name": "...", "metadata": <OperationMetadata JSON>} describing a single
+// poll result, emits the resulting CloudEvents, and forwards them to Sink.
+// The metadata field is decoded with protojson (via the genomics package's
+// UnmarshalJSON), not encoding/json: a real poll response, like
+// every other protojson-encoded payload in this API, uses proto3's
+// camelCase field names (projectId, createTime, ...), which don't match
+// OperationMetadata's snake_case json struct tags. Decoding that with
+// plain encoding/json doesn't error - it just silently leaves every field
+// zeroed - so NewEmitter would fire events built from an empty metadata.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var poll struct {
+		Name        string           `json:"name"`
+		Metadata    json.RawMessage  `json:"metadata"`
+		TerminalErr *statuspb.Status `json:"error,omitempty"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&poll); err != nil {
+		http.Error(w, fmt.Sprintf("decoding poll body: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	var metadata *genomics.OperationMetadata
+	if len(poll.Metadata) > 0 && string(poll.Metadata) != "null" {
+		metadata = &genomics.OperationMetadata{}
+		if err := genomics.UnmarshalJSON(poll.Metadata, metadata); err != nil {
+			http.Error(w, fmt.Sprintf("decoding poll metadata: %s", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	h.mu.Lock()
+	if h.last == nil {
+		h.last = make(map[string]*genomics.OperationMetadata)
+	}
+	prev := h.last[poll.Name]
+	h.last[poll.Name] = metadata
+	h.mu.Unlock()
+
+	evs := NewEmitter(prev, metadata, poll.Name, poll.TerminalErr)
+
+	if err := h.forward(r.Context(), evs); err != nil {
+		http.Error(w, fmt.Sprintf("forwarding events to sink: %s", err), http.StatusBadGateway)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) forward(ctx context.Context, evs []cloudevents.Event) error {
+	for _, ev := range evs {
+		body, err := json.Marshal(ev)
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.Sink, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", cloudevents.ApplicationCloudEventsJSON)
+		resp, err := h.client().Do(req)
+		if err != nil {
+			return err
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("sink %s returned status %d", h.Sink, resp.StatusCode)
+		}
+	}
+	return nil
+}
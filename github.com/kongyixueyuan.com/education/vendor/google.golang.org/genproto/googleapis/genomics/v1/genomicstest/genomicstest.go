@@ -0,0 +1,433 @@
+// Copyright 2016 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package genomicstest provides an in-process fake implementation of
+// google.longrunning.Operations for testing code that consumes Genomics
+// long-running operations, without hand-mocking the generated pb types.
+package genomicstest
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	fuzz "github.com/google/gofuzz"
+
+	"github.com/google/btree"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	longrunningpb "google.golang.org/genproto/googleapis/longrunning"
+	statuspb "google.golang.org/genproto/googleapis/rpc/status"
+
+	genomics "google.golang.org/genproto/googleapis/genomics/v1"
+)
+
+// entry is the btree item backing a single stored operation. The tree is
+// ordered by (ProjectId, CreateTime, Name), and ListOperations returns
+// matches in that order. ListOperations visits every entry and applies the
+// filter to each one; it does not do a range scan over the ordering.
+type entry struct {
+	projectID  string
+	createTime int64 // unix nanos
+	name       string
+
+	op       *longrunningpb.Operation
+	metadata *genomics.OperationMetadata
+}
+
+func (e *entry) Less(than btree.Item) bool {
+	o := than.(*entry)
+	if e.projectID != o.projectID {
+		return e.projectID < o.projectID
+	}
+	if e.createTime != o.createTime {
+		return e.createTime < o.createTime
+	}
+	return e.name < o.name
+}
+
+// FakeOperations is an in-memory google.longrunning.Operations server. The
+// zero value is ready to use.
+type FakeOperations struct {
+	longrunningpb.UnimplementedOperationsServer
+
+	mu      sync.Mutex
+	tree    *btree.BTree
+	byName  map[string]*entry
+	nextTok int
+	tokens  map[string][]string // page token -> remaining names, for ListOperations
+}
+
+// degree is the btree branching factor; 32 is a reasonable default for an
+// in-memory index sized for tests, not production traffic.
+const degree = 32
+
+func (f *FakeOperations) init() {
+	if f.tree == nil {
+		f.tree = btree.New(degree)
+		f.byName = make(map[string]*entry)
+		f.tokens = make(map[string][]string)
+	}
+}
+
+// Create registers a new, not-yet-done operation with the given name,
+// project and creation time, returning an error if name is already in use.
+func (f *FakeOperations) Create(name, projectID string, createTime *timestamppb.Timestamp) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.init()
+
+	if _, found := f.byName[name]; found {
+		return fmt.Errorf("genomicstest: operation %q already exists", name)
+	}
+
+	meta := &genomics.OperationMetadata{
+		ProjectId:  projectID,
+		CreateTime: createTime,
+	}
+	anyMeta, err := anypb.New(meta)
+	if err != nil {
+		return err
+	}
+	e := &entry{
+		projectID:  projectID,
+		createTime: createTime.AsTime().UnixNano(),
+		name:       name,
+		op:         &longrunningpb.Operation{Name: name, Metadata: anyMeta},
+		metadata:   meta,
+	}
+	f.tree.ReplaceOrInsert(e)
+	f.byName[name] = e
+	return nil
+}
+
+// Advance appends ev to the named operation's Events and refreshes
+// StartTime if this is the operation's first event.
+func (f *FakeOperations) Advance(name string, ev *genomics.OperationEvent) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	e, err := f.lookup(name)
+	if err != nil {
+		return err
+	}
+
+	if e.metadata.StartTime == nil {
+		e.metadata.StartTime = ev.GetStartTime()
+	}
+	e.metadata.Events = append(e.metadata.Events, ev)
+	return f.repackMetadata(e)
+}
+
+// Complete marks the named operation Done with the given response and sets
+// EndTime.
+func (f *FakeOperations) Complete(name string, response proto.Message, endTime *timestamppb.Timestamp) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	e, err := f.lookup(name)
+	if err != nil {
+		return err
+	}
+
+	e.metadata.EndTime = endTime
+	if err := f.repackMetadata(e); err != nil {
+		return err
+	}
+
+	anyResp, err := anypb.New(response)
+	if err != nil {
+		return err
+	}
+	e.op.Done = true
+	e.op.Result = &longrunningpb.Operation_Response{Response: anyResp}
+	return nil
+}
+
+// Fail marks the named operation Done with the given terminal status and
+// sets EndTime.
+func (f *FakeOperations) Fail(name string, st *statuspb.Status, endTime *timestamppb.Timestamp) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	e, err := f.lookup(name)
+	if err != nil {
+		return err
+	}
+	return f.failLocked(e, st, endTime)
+}
+
+// failLocked is the shared implementation of Fail and CancelOperation. It
+// must be called with f.mu held, so the read of e and the write that fails
+// it happen as one atomic step with no window for a concurrent Advance,
+// Complete or Fail on the same entry to interleave.
+func (f *FakeOperations) failLocked(e *entry, st *statuspb.Status, endTime *timestamppb.Timestamp) error {
+	e.metadata.EndTime = endTime
+	if err := f.repackMetadata(e); err != nil {
+		return err
+	}
+
+	e.op.Done = true
+	e.op.Result = &longrunningpb.Operation_Error{Error: st}
+	return nil
+}
+
+func (f *FakeOperations) lookup(name string) (*entry, error) {
+	f.init()
+	e, found := f.byName[name]
+	if !found {
+		return nil, fmt.Errorf("genomicstest: no such operation %q", name)
+	}
+	return e, nil
+}
+
+func (f *FakeOperations) repackMetadata(e *entry) error {
+	anyMeta, err := anypb.New(e.metadata)
+	if err != nil {
+		return err
+	}
+	e.op.Metadata = anyMeta
+	return nil
+}
+
+// GetOperation implements longrunningpb.OperationsServer.
+func (f *FakeOperations) GetOperation(ctx context.Context, req *longrunningpb.GetOperationRequest) (*longrunningpb.Operation, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	e, err := f.lookup(req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	return e.op, nil
+}
+
+// CancelOperation implements longrunningpb.OperationsServer by marking the
+// operation Done with a Cancelled status, matching real service behavior
+// for operations that support synchronous cancellation.
+func (f *FakeOperations) CancelOperation(ctx context.Context, req *longrunningpb.CancelOperationRequest) (*emptypb.Empty, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	e, err := f.lookup(req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	st := &statuspb.Status{Code: int32(codes.Canceled), Message: "operation cancelled"}
+	if err := f.failLocked(e, st, timestamppb.Now()); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// DeleteOperation implements longrunningpb.OperationsServer.
+func (f *FakeOperations) DeleteOperation(ctx context.Context, req *longrunningpb.DeleteOperationRequest) (*emptypb.Empty, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	e, err := f.lookup(req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	f.tree.Delete(e)
+	delete(f.byName, e.name)
+	return &emptypb.Empty{}, nil
+}
+
+const defaultPageSize = 100
+
+// ListOperations implements longrunningpb.OperationsServer, supporting a
+// small subset of the filter grammar documented for the Genomics API:
+// `projectId=X`, `createTime>=RFC3339`, `createTime<=RFC3339` and
+// `labels.KEY=VALUE` clauses joined with `AND`.
+func (f *FakeOperations) ListOperations(ctx context.Context, req *longrunningpb.ListOperationsRequest) (*longrunningpb.ListOperationsResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.init()
+
+	pred, err := parseFilter(req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	if req.GetPageToken() != "" {
+		remaining, found := f.tokens[req.GetPageToken()]
+		if !found {
+			return nil, fmt.Errorf("genomicstest: unknown page token %q", req.GetPageToken())
+		}
+		names = remaining
+		delete(f.tokens, req.GetPageToken())
+	} else {
+		f.tree.Ascend(func(i btree.Item) bool {
+			e := i.(*entry)
+			if pred(e) {
+				names = append(names, e.name)
+			}
+			return true
+		})
+	}
+
+	pageSize := int(req.GetPageSize())
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+
+	resp := &longrunningpb.ListOperationsResponse{}
+	for i, name := range names {
+		if i >= pageSize {
+			f.nextTok++
+			token := strconv.Itoa(f.nextTok)
+			f.tokens[token] = names[i:]
+			resp.NextPageToken = token
+			break
+		}
+		resp.Operations = append(resp.Operations, f.byName[name].op)
+	}
+	return resp, nil
+}
+
+// parseFilter compiles a small `AND`-joined clause list into a predicate
+// over btree entries. Unsupported clauses produce an error rather than
+// silently matching everything.
+func parseFilter(filter string) (func(*entry) bool, error) {
+	filter = strings.TrimSpace(filter)
+	if filter == "" {
+		return func(*entry) bool { return true }, nil
+	}
+
+	var preds []func(*entry) bool
+	for _, clause := range strings.Split(filter, " AND ") {
+		clause = strings.TrimSpace(clause)
+		pred, err := parseClause(clause)
+		if err != nil {
+			return nil, err
+		}
+		preds = append(preds, pred)
+	}
+	return func(e *entry) bool {
+		for _, p := range preds {
+			if !p(e) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+func parseClause(clause string) (func(*entry) bool, error) {
+	switch {
+	case strings.HasPrefix(clause, "projectId="):
+		want := strings.TrimPrefix(clause, "projectId=")
+		return func(e *entry) bool { return e.projectID == want }, nil
+
+	case strings.HasPrefix(clause, "createTime>="):
+		t, err := parseRFC3339(strings.TrimPrefix(clause, "createTime>="))
+		if err != nil {
+			return nil, err
+		}
+		return func(e *entry) bool { return e.createTime >= t }, nil
+
+	case strings.HasPrefix(clause, "createTime<="):
+		t, err := parseRFC3339(strings.TrimPrefix(clause, "createTime<="))
+		if err != nil {
+			return nil, err
+		}
+		return func(e *entry) bool { return e.createTime <= t }, nil
+
+	case strings.HasPrefix(clause, "labels."):
+		kv := strings.TrimPrefix(clause, "labels.")
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("genomicstest: malformed labels clause %q", clause)
+		}
+		key, val := parts[0], parts[1]
+		return func(e *entry) bool { return e.metadata.GetLabels()[key] == val }, nil
+
+	default:
+		return nil, fmt.Errorf("genomicstest: unsupported filter clause %q", clause)
+	}
+}
+
+func parseRFC3339(s string) (int64, error) {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return 0, fmt.Errorf("genomicstest: invalid RFC3339 timestamp %q: %w", s, err)
+	}
+	return t.UnixNano(), nil
+}
+
+// RandomMetadata uses f to generate an internally-consistent
+// OperationMetadata for property tests: CreateTime <= StartTime <= EndTime,
+// every event's EndTime >= StartTime, and Labels keys/values that satisfy
+// the GCP label constraints so the result round-trips through Validate.
+func RandomMetadata(f *fuzz.Fuzzer) *genomics.OperationMetadata {
+	var projectID string
+	var numEvents uint8
+	var numLabels uint8
+	f.Fuzz(&projectID)
+	f.Fuzz(&numEvents)
+	f.Fuzz(&numLabels)
+	if projectID == "" {
+		projectID = "fuzz-project"
+	}
+
+	create := randTimestamp(f)
+	start := offsetTimestamp(create, f)
+	end := offsetTimestamp(start, f)
+
+	meta := &genomics.OperationMetadata{
+		ProjectId:  projectID,
+		CreateTime: create,
+		StartTime:  start,
+		EndTime:    end,
+		Labels:     make(map[string]string),
+	}
+
+	for i := uint8(0); i < numEvents%8; i++ {
+		evStart := offsetTimestamp(start, f)
+		evEnd := offsetTimestamp(evStart, f)
+		var description string
+		f.Fuzz(&description)
+		if description == "" {
+			description = fmt.Sprintf("event-%d", i)
+		}
+		meta.Events = append(meta.Events, &genomics.OperationEvent{
+			StartTime:   evStart,
+			EndTime:     evEnd,
+			Description: description,
+		})
+	}
+
+	for i := uint8(0); i < numLabels%5; i++ {
+		meta.Labels[fmt.Sprintf("fuzz-key-%d", i)] = fmt.Sprintf("fuzz-value-%d", i)
+	}
+
+	return meta
+}
+
+func randTimestamp(f *fuzz.Fuzzer) *timestamppb.Timestamp {
+	var days uint16
+	f.Fuzz(&days)
+	return timestamppb.New(time.Unix(0, 0).Add(time.Duration(days%3650) * 24 * time.Hour))
+}
+
+// offsetTimestamp nudges base forward by a fuzzed, non-negative duration so
+// the resulting chain of timestamps stays monotonically increasing.
+func offsetTimestamp(base *timestamppb.Timestamp, f *fuzz.Fuzzer) *timestamppb.Timestamp {
+	var minutes uint16
+	f.Fuzz(&minutes)
+	return timestamppb.New(base.AsTime().Add(time.Duration(minutes) * time.Minute))
+}
@@ -0,0 +1,241 @@
+// Copyright 2016 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lro wraps the google.longrunning.Operations service with a typed
+// handle for Genomics operations, so callers don't have to poll raw
+// longrunningpb.Operation/genomics.OperationMetadata values by hand.
+package lro
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	longrunningpb "google.golang.org/genproto/googleapis/longrunning"
+	statuspb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	genomics "google.golang.org/genproto/googleapis/genomics/v1"
+)
+
+// PollConfig controls how Wait polls the Operations service.
+type PollConfig struct {
+	// InitialInterval is the delay between Wait's first poll, which is made
+	// immediately, and its second. Defaults to 1s if zero.
+	InitialInterval time.Duration
+	// MaxInterval caps the backoff between polls. Defaults to 30s if zero.
+	MaxInterval time.Duration
+	// Multiplier is applied to the interval after every poll. Defaults to 1.5
+	// if zero.
+	Multiplier float64
+}
+
+func (c PollConfig) withDefaults() PollConfig {
+	if c.InitialInterval <= 0 {
+		c.InitialInterval = time.Second
+	}
+	if c.MaxInterval <= 0 {
+		c.MaxInterval = 30 * time.Second
+	}
+	if c.Multiplier <= 0 {
+		c.Multiplier = 1.5
+	}
+	return c
+}
+
+// WaitOption configures a call to GenomicsOperation.Wait.
+type WaitOption func(*PollConfig)
+
+// WithPollConfig overrides the default backoff used while polling.
+func WithPollConfig(cfg PollConfig) WaitOption {
+	return func(c *PollConfig) { *c = cfg }
+}
+
+// OperationError is returned by Wait and Poll when the underlying operation
+// finished with an error, carrying the google.rpc.Status the service
+// reported.
+type OperationError struct {
+	Name   string
+	Status *statuspb.Status
+}
+
+func (e *OperationError) Error() string {
+	return fmt.Sprintf("genomics operation %q failed: %s", e.Name, e.Status.GetMessage())
+}
+
+// GRPCStatus lets errors.As/status.FromError recover the underlying gRPC
+// status code from an *OperationError.
+func (e *OperationError) GRPCStatus() *status.Status {
+	return status.FromProto(e.Status)
+}
+
+// GenomicsOperation is a typed handle around a single
+// google.longrunning.Operation produced by the Genomics API.
+type GenomicsOperation struct {
+	name   string
+	client longrunningpb.OperationsClient
+
+	seen map[string]struct{}
+}
+
+// NewGenomicsOperation wraps an existing operation name (as returned by
+// a Genomics RPC) with a handle for polling, cancellation and event
+// streaming.
+func NewGenomicsOperation(client longrunningpb.OperationsClient, name string) *GenomicsOperation {
+	return &GenomicsOperation{
+		name:   name,
+		client: client,
+		seen:   make(map[string]struct{}),
+	}
+}
+
+// Name returns the fully-qualified operation resource name.
+func (o *GenomicsOperation) Name() string { return o.name }
+
+// Poll fetches the latest OperationMetadata without blocking for
+// completion.
+func (o *GenomicsOperation) Poll(ctx context.Context) (*genomics.OperationMetadata, bool, error) {
+	op, err := o.client.GetOperation(ctx, &longrunningpb.GetOperationRequest{Name: o.name})
+	if err != nil {
+		return nil, false, err
+	}
+	meta, err := unpackMetadata(op)
+	if err != nil {
+		return nil, op.GetDone(), err
+	}
+	return meta, op.GetDone(), operationError(o.name, op)
+}
+
+// Wait polls the operation with exponential backoff until it is Done,
+// returning the final OperationMetadata. It returns an *OperationError if
+// the operation completed with an error, and stops early if ctx is
+// cancelled.
+func (o *GenomicsOperation) Wait(ctx context.Context, opts ...WaitOption) (*genomics.OperationMetadata, error) {
+	cfg := PollConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	cfg = cfg.withDefaults()
+
+	interval := cfg.InitialInterval
+	for {
+		meta, done, err := o.Poll(ctx)
+		if done {
+			return meta, err
+		}
+		if err != nil {
+			return meta, err
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return meta, ctx.Err()
+		case <-timer.C:
+		}
+
+		interval = time.Duration(float64(interval) * cfg.Multiplier)
+		if interval > cfg.MaxInterval {
+			interval = cfg.MaxInterval
+		}
+	}
+}
+
+// Cancel requests that the operation be cancelled. Cancellation is best
+// effort; the operation may still run to completion.
+func (o *GenomicsOperation) Cancel(ctx context.Context) error {
+	_, err := o.client.CancelOperation(ctx, &longrunningpb.CancelOperationRequest{Name: o.name})
+	return err
+}
+
+// Events returns a channel of OperationEvents that the operation has
+// recorded, emitting newly observed events (deduplicated by StartTime and
+// Description) as they show up on subsequent polls. The channel is closed
+// once the operation is Done or ctx is cancelled.
+func (o *GenomicsOperation) Events(ctx context.Context) <-chan *genomics.OperationEvent {
+	out := make(chan *genomics.OperationEvent)
+	go func() {
+		defer close(out)
+		ticker := time.NewTicker(2 * time.Second)
+		defer ticker.Stop()
+
+		for {
+			meta, done, err := o.Poll(ctx)
+			if err != nil && meta == nil {
+				return
+			}
+			for _, ev := range meta.GetEvents() {
+				key := eventKey(ev)
+				if _, ok := o.seen[key]; ok {
+					continue
+				}
+				o.seen[key] = struct{}{}
+				select {
+				case out <- ev:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if done {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return out
+}
+
+func eventKey(ev *genomics.OperationEvent) string {
+	return ev.GetStartTime().String() + "|" + ev.GetDescription()
+}
+
+// As unpacks an anypb.Any (typically OperationMetadata.Request or
+// RuntimeMetadata) into a caller-supplied proto.Message type.
+func As[T proto.Message](a *anypb.Any) (T, error) {
+	var zero T
+	msg := zero.ProtoReflect().New().Interface()
+	if err := a.UnmarshalTo(msg); err != nil {
+		return zero, err
+	}
+	return msg.(T), nil
+}
+
+func unpackMetadata(op *longrunningpb.Operation) (*genomics.OperationMetadata, error) {
+	if op.GetMetadata() == nil {
+		return nil, nil
+	}
+	meta := &genomics.OperationMetadata{}
+	if err := op.GetMetadata().UnmarshalTo(meta); err != nil {
+		return nil, fmt.Errorf("unmarshaling operation metadata for %q: %w", op.GetName(), err)
+	}
+	return meta, nil
+}
+
+func operationError(name string, op *longrunningpb.Operation) error {
+	if !op.GetDone() {
+		return nil
+	}
+	if st := op.GetError(); st != nil {
+		return &OperationError{Name: name, Status: st}
+	}
+	return nil
+}
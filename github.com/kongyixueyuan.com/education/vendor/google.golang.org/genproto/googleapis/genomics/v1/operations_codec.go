@@ -0,0 +1,134 @@
+// Copyright 2016 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genomics
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/ghodss/yaml"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// maxLabelCount, maxLabelLength and labelKeyPattern mirror the GCP resource-label constraints
+// documented for Labels on OperationMetadata.
+const (
+	maxLabelCount  = 64
+	maxLabelLength = 63
+)
+
+var labelKeyPattern = regexp.MustCompile(`^[a-z]([-a-z0-9]*[a-z0-9])?$`)
+
+// defaultResolver resolves the Any types this package's current API version
+// knows about (OperationMetadata.Request and RuntimeMetadata). Callers with
+// custom request/runtime-metadata messages should build their own
+// protoregistry.Types and pass it to UnmarshalJSONWithResolver instead.
+var defaultResolver AnyResolver = protoregistry.GlobalTypes
+
+// MarshalJSON renders an OperationMetadata using protojson, producing the
+// standard proto3 camelCase field names.
+func MarshalJSON(m *OperationMetadata) ([]byte, error) {
+	return protojson.MarshalOptions{}.Marshal(m)
+}
+
+// AnyResolver resolves the concrete message type behind an Any field while
+// unmarshaling. protoregistry.Types (and in particular
+// protoregistry.GlobalTypes) satisfies this.
+type AnyResolver interface {
+	protoregistry.MessageTypeResolver
+	protoregistry.ExtensionTypeResolver
+}
+
+// UnmarshalJSON parses protojson-encoded bytes into m, unpacking any Any
+// fields using the package's default type resolver.
+func UnmarshalJSON(data []byte, m *OperationMetadata) error {
+	return UnmarshalJSONWithResolver(data, m, defaultResolver)
+}
+
+// UnmarshalJSONWithResolver parses protojson-encoded bytes into m, using
+// resolver to unpack Any fields (such as Request and RuntimeMetadata) whose
+// concrete type isn't registered globally.
+func UnmarshalJSONWithResolver(data []byte, m *OperationMetadata, resolver AnyResolver) error {
+	return protojson.UnmarshalOptions{Resolver: resolver}.Unmarshal(data, m)
+}
+
+// MarshalYAML renders an OperationMetadata as YAML, going through the
+// protojson encoding first so field naming and Any handling match
+// MarshalJSON.
+func MarshalYAML(m *OperationMetadata) ([]byte, error) {
+	jsonBytes, err := MarshalJSON(m)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.JSONToYAML(jsonBytes)
+}
+
+// UnmarshalYAML parses a YAML document into m by converting it to JSON and
+// delegating to UnmarshalJSON.
+func UnmarshalYAML(data []byte, m *OperationMetadata) error {
+	jsonBytes, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return err
+	}
+	return UnmarshalJSON(jsonBytes, m)
+}
+
+// Validate checks m against the invariants the Genomics API documents but
+// the generated type can't enforce on its own: a non-empty ProjectId,
+// CreateTime <= StartTime <= EndTime (when set), per-event EndTime >=
+// StartTime and a required Description, and GCP-compliant Labels.
+func (m *OperationMetadata) Validate() error {
+	if m.GetProjectId() == "" {
+		return fmt.Errorf("operation metadata: project_id is required")
+	}
+
+	create, start, end := m.GetCreateTime(), m.GetStartTime(), m.GetEndTime()
+	if start != nil && create != nil && start.AsTime().Before(create.AsTime()) {
+		return fmt.Errorf("operation metadata: start_time %s precedes create_time %s", start.AsTime(), create.AsTime())
+	}
+	if end != nil && start != nil && end.AsTime().Before(start.AsTime()) {
+		return fmt.Errorf("operation metadata: end_time %s precedes start_time %s", end.AsTime(), start.AsTime())
+	}
+	if end != nil && create != nil && start == nil && end.AsTime().Before(create.AsTime()) {
+		return fmt.Errorf("operation metadata: end_time %s precedes create_time %s", end.AsTime(), create.AsTime())
+	}
+
+	for i, ev := range m.GetEvents() {
+		if ev.GetDescription() == "" {
+			return fmt.Errorf("operation metadata: events[%d]: description is required", i)
+		}
+		if ev.GetEndTime() != nil && ev.GetStartTime() != nil && ev.GetEndTime().AsTime().Before(ev.GetStartTime().AsTime()) {
+			return fmt.Errorf("operation metadata: events[%d]: end_time %s precedes start_time %s", i, ev.GetEndTime().AsTime(), ev.GetStartTime().AsTime())
+		}
+	}
+
+	return validateLabels(m.GetLabels())
+}
+
+func validateLabels(labels map[string]string) error {
+	if len(labels) > maxLabelCount {
+		return fmt.Errorf("operation metadata: labels: at most %d labels are allowed, got %d", maxLabelCount, len(labels))
+	}
+	for k, v := range labels {
+		if len(k) > maxLabelLength || !labelKeyPattern.MatchString(k) {
+			return fmt.Errorf("operation metadata: labels: key %q does not satisfy %s", k, labelKeyPattern)
+		}
+		if len(v) > maxLabelLength {
+			return fmt.Errorf("operation metadata: labels: value %q for key %q exceeds %d characters", v, k, maxLabelLength)
+		}
+	}
+	return nil
+}